@@ -0,0 +1,189 @@
+package scotty
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a size in bytes which is given in human-readable units,
+// e.g. 512KiB, 10MB or 1.5GiB. Both SI units (KB, MB, ...) which are
+// powers of 1000 and IEC units (KiB, MiB, ...) which are powers of 1024
+// are supported. A number without a unit is a number of bytes.
+type ByteSize uint64
+
+// SI units of ByteSize.
+const (
+	KB ByteSize = 1000
+	MB          = 1000 * KB
+	GB          = 1000 * MB
+	TB          = 1000 * GB
+	PB          = 1000 * TB
+	EB          = 1000 * PB
+)
+
+// IEC units of ByteSize.
+const (
+	KiB ByteSize = 1 << (10 * (iota + 1))
+	MiB
+	GiB
+	TiB
+	PiB
+	EiB
+)
+
+// byteSizeUnit is the unit of ByteSize with its name.
+type byteSizeUnit struct {
+	name string
+	size ByteSize
+}
+
+// byteSizeUnits holds the units of ByteSize from the largest to the smallest.
+var byteSizeUnits = []byteSizeUnit{
+	{"EiB", EiB}, {"EB", EB},
+	{"PiB", PiB}, {"PB", PB},
+	{"TiB", TiB}, {"TB", TB},
+	{"GiB", GiB}, {"GB", GB},
+	{"MiB", MiB}, {"MB", MB},
+	{"KiB", KiB}, {"KB", KB},
+	{"B", 1},
+}
+
+// errByteSizeOverflow is returned when the size doesn't fit into ByteSize.
+var errByteSizeOverflow = errors.New("value out of range")
+
+// ParseByteSize parses the size in bytes given as a decimal number followed
+// by an optional unit, e.g. 512KiB, 10MB or 1.5GiB. Units are case-insensitive.
+// Fractions of a byte are rounded down. Sizes which don't fit into ByteSize
+// are reported as errors.
+func ParseByteSize(s string) (ByteSize, error) {
+	text := strings.TrimSpace(s)
+
+	number := strings.TrimRight(text, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	unitName := strings.TrimSpace(text[len(number):])
+	number = strings.TrimSpace(number)
+
+	unit := ByteSize(1)
+
+	if unitName != "" {
+		i := slices.IndexFunc(byteSizeUnits, func(u byteSizeUnit) bool { return strings.EqualFold(u.name, unitName) })
+		if i < 0 {
+			return 0, fmt.Errorf("invalid size %q: unknown unit %q", s, unitName)
+		}
+
+		unit = byteSizeUnits[i].size
+	}
+
+	size, err := scaleByteSize(number, unit)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+
+	return size, nil
+}
+
+// scaleByteSize multiplies the decimal number by the unit
+// reporting the results which don't fit into ByteSize.
+func scaleByteSize(number string, unit ByteSize) (ByteSize, error) {
+	intPart, fracPart, _ := strings.Cut(number, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, errors.New("missing number")
+	}
+
+	var whole uint64
+
+	if intPart != "" {
+		parsed, err := strconv.ParseUint(intPart, 10, 64)
+		if err != nil {
+			return 0, errors.New("invalid number")
+		}
+
+		whole = parsed
+	}
+
+	hi, size := bits.Mul64(whole, uint64(unit))
+	if hi != 0 {
+		return 0, errByteSizeOverflow
+	}
+
+	if fracPart == "" {
+		return ByteSize(size), nil
+	}
+
+	// Digits beyond the precision of uint64 can't change the result much.
+	const maxFracDigits = 18
+	fracPart = fracPart[:min(len(fracPart), maxFracDigits)]
+
+	frac, err := strconv.ParseUint(fracPart, 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid number")
+	}
+
+	denominator := uint64(1)
+	for range fracPart {
+		denominator *= 10
+	}
+
+	// The fraction is less than one, so the product divided by
+	// the denominator is less than the unit and doesn't overflow.
+	hi, lo := bits.Mul64(frac, uint64(unit))
+	fraction, _ := bits.Div64(hi, lo, denominator)
+
+	size, carry := bits.Add64(size, fraction, 0)
+	if carry != 0 {
+		return 0, errByteSizeOverflow
+	}
+
+	return ByteSize(size), nil
+}
+
+// String formats the size using the largest unit which divides
+// the size without a remainder, e.g. 512KiB or 10MB.
+func (b ByteSize) String() string {
+	if b == 0 {
+		return "0B"
+	}
+
+	for _, unit := range byteSizeUnits {
+		if b%unit.size == 0 {
+			return strconv.FormatUint(uint64(b/unit.size), 10) + unit.name
+		}
+	}
+
+	return strconv.FormatUint(uint64(b), 10) + "B"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	parsed, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+
+	*b = parsed
+
+	return nil
+}
+
+// ByteSizeVar defines a ByteSize flag with specified name, default value, and usage string.
+// The argument p points to a ByteSize variable in which to store the value of the flag.
+// The flag takes sizes in human-readable units: -max-upload 10MB or -cache 1.5GiB.
+func (f *FlagSet) ByteSizeVar(p *ByteSize, name string, value ByteSize, usage string) {
+	f.ByteSizeVarE(p, name, "", value, usage)
+}
+
+// ByteSizeVarE defines a ByteSize flag and environment variable with specified name, default value, and usage string.
+// The argument p points to a ByteSize variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+func (f *FlagSet) ByteSizeVarE(p *ByteSize, flagName, envName string, value ByteSize, usage string) {
+	varE(f, newGenericValue(p, "size", ParseByteSize, ByteSize.String), flagName, envName, value, usage)
+}
@@ -0,0 +1,126 @@
+package scotty
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	type tcase struct {
+		in      string
+		want    ByteSize
+		wantErr bool
+	}
+
+	tests := map[string]tcase{
+		"Bytes":          {in: "123", want: 123},
+		"Bytes unit":     {in: "123B", want: 123},
+		"SI":             {in: "10MB", want: 10_000_000},
+		"IEC":            {in: "512KiB", want: 512 * 1024},
+		"Fraction":       {in: "1.5GiB", want: 1536 * MiB},
+		"Leading dot":    {in: ".5KB", want: 500},
+		"Rounded down":   {in: "1.0005KB", want: 1000},
+		"Case":           {in: "2 gib", want: 2 * GiB},
+		"Max":            {in: "18446744073709551615", want: 1<<64 - 1},
+		"Largest unit":   {in: "15EiB", want: 15 * EiB},
+		"Overflow":       {in: "16EiB", wantErr: true},
+		"Fract overflow": {in: "18446744073709551.616KB", wantErr: true},
+		"Unknown unit":   {in: "10XB", wantErr: true},
+		"Negative":       {in: "-1KB", wantErr: true},
+		"Missing number": {in: "KB", wantErr: true},
+		"Invalid number": {in: "1.2.3MB", wantErr: true},
+		"Empty":          {in: "", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseByteSize(tc.in)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %v", got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.want != got {
+				t.Errorf("want := %d, got := %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestByteSize_String(t *testing.T) {
+	tests := map[ByteSize]string{
+		0:           "0B",
+		100:         "100B",
+		1023:        "1023B",
+		512 * KiB:   "512KiB",
+		10 * MB:     "10MB",
+		1536 * MiB:  "1536MiB",
+		1000 * KiB:  "1000KiB",
+		3 * EiB:     "3EiB",
+		1<<64 - 1:   "18446744073709551615B",
+		2*GB + 1000: "2000001KB",
+	}
+
+	for size, want := range tests {
+		if got := size.String(); got != want {
+			t.Errorf("want := %q, got := %q", want, got)
+		}
+
+		parsed, err := ParseByteSize(want)
+		if err != nil || parsed != size {
+			t.Errorf("round trip of %q: got := %d, %v", want, parsed, err)
+		}
+	}
+}
+
+func TestFlagSet_ByteSizeVarE(t *testing.T) {
+	t.Setenv("TEST_SIZE", "lalala")
+
+	f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+	f.SetOutput(discard{})
+
+	var got ByteSize
+
+	f.ByteSizeVarE(&got, "size", "TEST_SIZE", 10*MiB, "")
+
+	if def := f.Lookup("size").DefValue; def != "10MiB" {
+		t.Errorf("want := %q, got := %q", "10MiB", def)
+	}
+
+	if err := f.Parse([]string{"-size", "1.5GB"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got != 1500*MB {
+		t.Errorf("want := %d, got := %d", 1500*MB, got)
+	}
+
+	if err := f.Parse([]string{"-size", "20EB"}); err == nil {
+		t.Error("expected overflow error, got nil")
+	}
+}
+
+func TestCommand_ByteSizeHelp(t *testing.T) {
+	var size ByteSize
+
+	cmd := &Command{Name: "app"}
+	cmd.Flags().ByteSizeVar(&size, "buffer", 512*KiB, "buffer size")
+
+	var b strings.Builder
+
+	if err := cmd.renderHelp(&b, terminal{width: 120}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "  -buffer size  buffer size (default: 512KiB)\n"; !strings.Contains(b.String(), want) {
+		t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+	}
+}
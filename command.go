@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"text/template"
 )
 
 // Command represents a program command.
@@ -19,6 +20,42 @@ type Command struct {
 	// Long represents short description of the command.
 	Long string
 
+	// Hidden hides the command from the list of
+	// available commands in the help output.
+	// Hidden command can still be executed.
+	Hidden bool
+
+	// Deprecated marks the command as deprecated and holds the message
+	// which explains what to use instead. Deprecated command still works,
+	// but prints a warning with the message the first time it is executed.
+	Deprecated string
+
+	// Group holds the ID of the group of the parent command's subcommands
+	// the command is listed in by the help output. See Command.AddGroup.
+	Group string
+
+	// SortGroups makes the help output list the groups of subcommands
+	// sorted by title instead of the order of their declaration.
+	SortGroups bool
+
+	// ArgsUsage describes the positional arguments of the command in
+	// the help output, e.g. "<source> <destination>". When empty
+	// the "[arguments...]" placeholder is shown.
+	ArgsUsage string
+
+	// Example holds examples of the command usage shown in the help output.
+	Example string
+
+	// HelpTemplate overrides the text/template of the help output for
+	// the command and all its subcommands which don't override it too.
+	// The template is executed with HelpData. See DefaultHelpTemplate.
+	HelpTemplate string
+
+	// HelpFuncs holds additional functions available in the help template
+	// of the command and all its subcommands. Functions of subcommands
+	// take precedence over the functions of their ancestors.
+	HelpFuncs template.FuncMap
+
 	// SetFlags represents function which can be used to set flags.
 	SetFlags func(flags *FlagSet)
 
@@ -37,11 +74,23 @@ type Command struct {
 	// flagsState holds state of flags initialization.
 	flagsState sync.Once
 
+	// deprecationWarning makes the deprecation warning to be printed once.
+	deprecationWarning sync.Once
+
 	// subcommands holds set of Command who are a subcommand to this Command.
 	subcommands map[string]*Command
 
 	// parent holds a pointer to a parent Command.
 	parent *Command
+
+	// groups holds the groups of subcommands in the order of declaration.
+	groups []commandGroup
+}
+
+// commandGroup represents a group of subcommands in the help output.
+type commandGroup struct {
+	id    string
+	title string
 }
 
 // Exec traverses to the root command and calls Command.execCommand.
@@ -52,8 +101,11 @@ func (c *Command) Exec() error {
 		flag.CommandLine = c.Flags().FlagSet
 	}
 
-	// Parse all the program arguments.
-	flag.Parse() //nolint:revive // CLI library intentionally calls flag.Parse in Exec.
+	// Parse all the program arguments. The errors of the environment
+	// variable values are reported by Command.execCommand.
+	if _, err := c.Flags().parse(os.Args[1:]); err != nil {
+		return fmt.Errorf("command failed: %w", err)
+	}
 
 	return c.execCommand(c.Flags().Args())
 }
@@ -90,6 +142,21 @@ func (c *Command) AddSubcommands(commands ...*Command) {
 	}
 }
 
+// AddGroup declares a group of subcommands with the given ID and title.
+// Subcommands refer to the group by its ID via the Command.Group field
+// and are listed in the help output in a section with the given title.
+// Subcommands without a group, or with an undeclared one, are listed
+// in the default section.
+func (c *Command) AddGroup(id, title string) {
+	for _, group := range c.groups {
+		if group.id == id {
+			panic(fmt.Errorf("group '%s' already added to '%s' command", id, c.Name))
+		}
+	}
+
+	c.groups = append(c.groups, commandGroup{id: id, title: title})
+}
+
 // IsSubcommand return whether the command is subcommand for another command.
 func (c *Command) IsSubcommand() bool {
 	if c.parent == nil || c.parent == c {
@@ -117,6 +184,10 @@ func (c *Command) Flags() *FlagSet {
 
 		c.flags.Usage = c.usage
 
+		if c.IsSubcommand() {
+			c.flags.parent = c.parent.Flags()
+		}
+
 		c.inheritPersistentFlags(c.flags)
 
 		if c.SetFlags != nil {
@@ -132,13 +203,14 @@ func (c *Command) Args() []string { return c.Flags().Args() }
 
 // BindConfig binds a config struct to the command's flagset.
 // The cfg argument must be a pointer to a struct with appropriate tags.
-// Tags supported: flag, env, default, usage, required.
+// Tags supported: flag, env, default, usage, required, placeholder, group,
+// hidden, deprecated. See BindOption for the options of binding.
 // Call this before Exec() to set up the binding.
-func (c *Command) BindConfig(cfg any) error {
+func (c *Command) BindConfig(cfg any, options ...BindOption) error {
 	flags := c.Flags()
 	flags.config = cfg
 
-	return bindConfigToFlagSet(flags, cfg)
+	return bindConfigToFlagSet(flags, cfg, newBindOptions(options))
 }
 
 // Config returns the bound config. Returns nil if no config was bound.
@@ -164,9 +236,25 @@ func (c *Command) inheritPersistentFlags(flags *FlagSet) {
 	// the snapshot so that already-parsed values survive the re-registration.
 	saved := snapshotSetFlags(c.flags)
 
+	// Remember which flags existed before the registration, so the flags
+	// defined by an ancestor can be marked as inherited. Such flags are
+	// shown in a separate section of the help output.
+	defined := make(map[string]bool)
+	flags.VisitAll(func(f *flag.Flag) { defined[f.Name] = true })
+
 	c.SetPersistentFlags(flags)
 
 	restoreSetFlags(flags, saved)
+
+	if c.flags == flags {
+		return
+	}
+
+	flags.VisitAll(func(f *flag.Flag) {
+		if !defined[f.Name] {
+			flags.metaFor(f.Name).inherited = true
+		}
+	})
 }
 
 // snapshotSetFlags captures the current name-value pairs of all defined flags.
@@ -184,35 +272,70 @@ func snapshotSetFlags(fs *FlagSet) map[string]string {
 			m = make(map[string]string)
 		}
 
-		m[f.Name] = f.Value.String()
+		m[f.Name] = rawString(f.Value)
 	})
 
 	return m
 }
 
 // restoreSetFlags writes previously-snapshotted values back into fs.
+// The values which the re-registration hasn't changed are skipped, so the flags
+// which have not been provided keep their defaults as if they were never set:
+// the first slice item still replaces the default, and the optional fields stay nil.
 func restoreSetFlags(fs *FlagSet, saved map[string]string) {
 	for name, val := range saved {
-		if f := fs.Lookup(name); f != nil {
+		if f := fs.Lookup(name); f != nil && rawString(f.Value) != val {
 			//nolint:errcheck // Value was already parsed successfully; Set cannot fail here.
 			f.Value.Set(val)
 		}
 	}
 }
 
+// inheritSources copies the sources of the persistent flag values parsed
+// by the parent, so the subcommand doesn't take their environment variables.
+func (c *Command) inheritSources(parent *FlagSet) {
+	flags := c.Flags()
+
+	parent.VisitAll(func(f *flag.Flag) {
+		source := parent.lookupMeta(f.Name).source
+		if source == SourceDefault || !flags.lookupMeta(f.Name).inherited {
+			return
+		}
+
+		flags.metaFor(f.Name).source = source
+	})
+}
+
 // execCommand parse and validates all flags and args executes the Run function.
 func (c *Command) execCommand(args []string) error {
-	if err := c.Flags().Parse(args); err != nil {
+	if c.Deprecated != "" {
+		c.deprecationWarning.Do(func() {
+			fmt.Fprintf(c.Flags().Output(), "Command %q is deprecated, %s\n", c.Name, c.Deprecated)
+		})
+	}
+
+	envErrs, err := c.Flags().parse(args)
+	if err != nil {
 		return fmt.Errorf("command failed: %w", err)
 	}
 
-	// Validate required fields if config is bound.
+	c.flags.warnDeprecated()
+
+	// Report the errors of environment variables, flag constraints, required
+	// fields and validation tags of the bound config all at once.
+	errs := append(envErrs, c.flags.validateConstraints()...)
+
 	if c.flags.config != nil {
-		if err := validateRequiredFields(c.flags.requiredFields); err != nil {
-			return fmt.Errorf("command failed: %w", err)
-		}
+		errs = append(errs, validateRequiredFields(c.flags)...)
+		errs = append(errs, validateFields(c.flags)...)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("command failed: %w", ConfigErrors(errs))
+	}
 
-		if err := validateConfig(c.flags.config); err != nil {
+	if c.flags.config != nil {
+		if err := validateConfig(c.flags); err != nil {
 			return fmt.Errorf("command failed: %w", err)
 		}
 	}
@@ -224,6 +347,8 @@ func (c *Command) execCommand(args []string) error {
 	if len(remaining) > 0 && len(c.subcommands) > 0 {
 		if subcommand, ok := c.subcommands[remaining[0]]; ok {
 			// Subcommand has been found and should be executed.
+			subcommand.inheritSources(c.flags)
+
 			return subcommand.execCommand(remaining[1:])
 		}
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -142,6 +143,26 @@ func TestCommand_AddSubcommands(t *testing.T) {
 	}
 }
 
+func TestCommand_AddGroup(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		cmd := &Command{Name: "root"}
+		cmd.AddGroup("debug", "Debug Commands")
+
+		want := []commandGroup{{id: "debug", title: "Debug Commands"}}
+		if !reflect.DeepEqual(cmd.groups, want) {
+			t.Errorf("Expected groups := %+v, got := %+v", want, cmd.groups)
+		}
+	})
+
+	t.Run("Panic duplicate", func(t *testing.T) {
+		defer helperCatchPanic(t, fmt.Errorf("group '%s' already added to '%s' command", "debug", "root"))
+
+		cmd := &Command{Name: "root"}
+		cmd.AddGroup("debug", "Debug Commands")
+		cmd.AddGroup("debug", "Other Commands")
+	})
+}
+
 // DO NOT RUN MANUALLY from GoLand, or VSCode by 'play' button.
 func TestCommand_Args(t *testing.T) {
 	helperDisableStdout(t)
@@ -533,6 +554,76 @@ func TestCommand_SetPersistentFlags(t *testing.T) {
 	})
 }
 
+func TestCommand_Deprecated(t *testing.T) {
+	var (
+		b    strings.Builder
+		addr string
+	)
+
+	root := &Command{Name: "root"}
+	sub := &Command{
+		Name:       "old",
+		Deprecated: "use new",
+		SetFlags: func(flags *FlagSet) {
+			flags.StringVar(&addr, "listen", "", "listen address")
+			flags.MarkDeprecated("listen", "use -addr")
+		},
+		Run: func(cmd *Command, args []string) error { return nil },
+	}
+
+	root.AddSubcommands(sub)
+	sub.Flags().SetOutput(&b)
+
+	for range 2 {
+		if err := root.execCommand([]string{"old", "-listen", ":80"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	want := "Command \"old\" is deprecated, use new\n" +
+		"Flag -listen is deprecated, use -addr\n"
+
+	if got := b.String(); got != want {
+		t.Errorf("Expected := %q, got := %q", want, got)
+	}
+
+	if addr != ":80" {
+		t.Errorf("Expected addr to be ':80', got '%s'", addr)
+	}
+}
+
+func TestCommand_DeprecatedEnv(t *testing.T) {
+	t.Setenv("TEST_LISTEN", ":80")
+
+	var (
+		b            strings.Builder
+		addr, legacy string
+	)
+
+	cmd := &Command{
+		Name: "app",
+		SetFlags: func(flags *FlagSet) {
+			flags.StringVarE(&addr, "listen", "TEST_LISTEN", "", "listen address")
+			flags.StringVarE(&legacy, "legacy", "TEST_LEGACY", "", "legacy mode")
+			flags.MarkDeprecated("listen", "use -addr")
+			flags.MarkDeprecated("legacy", "no longer needed")
+		},
+		Run: func(cmd *Command, args []string) error { return nil },
+	}
+
+	cmd.Flags().SetOutput(&b)
+
+	if err := cmd.execCommand(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := "Flag -listen set by TEST_LISTEN is deprecated, use -addr\n"
+
+	if got := b.String(); got != want {
+		t.Errorf("Expected := %q, got := %q", want, got)
+	}
+}
+
 func helperDisableStdout(t *testing.T) {
 	tmpStdout := os.Stdout
 	tmpStderr := os.Stderr
@@ -564,3 +655,133 @@ func helperSetTestingFlags(t *testing.T, cmd *Command) {
 	cmd.Flags().String("test.coverprofile", "", "")
 	cmd.Flags().String("test.gocoverdir", "", "")
 }
+
+func TestCommand_StrictEnv(t *testing.T) {
+	helperDisableStdout(t)
+
+	t.Setenv("TEST_PORT", "80a")
+
+	var port int
+
+	cmd := &Command{
+		Name: "test",
+		SetFlags: func(flags *FlagSet) {
+			flags.SetStrictEnv(true)
+			flags.IntVarE(&port, "port", "TEST_PORT", 8080, "port")
+		},
+		Run: func(cmd *Command, args []string) error { return nil },
+	}
+
+	err := cmd.execCommand(nil)
+
+	var envErr *EnvParseError
+	if !errors.As(err, &envErr) {
+		t.Fatalf("expected EnvParseError, got %v", err)
+	}
+
+	if envErr.Env != "TEST_PORT" || envErr.Flag != "port" {
+		t.Errorf("unexpected error details: %+v", envErr)
+	}
+}
+
+func TestCommand_AutoEnv(t *testing.T) {
+	helperDisableStdout(t)
+
+	t.Setenv("MYAPP_VERBOSE", "true")
+	t.Setenv("MYAPP_FORMAT", "json")
+
+	var (
+		verbose bool
+		format  string
+	)
+
+	root := &Command{
+		Name: "root",
+		SetFlags: func(flags *FlagSet) {
+			flags.SetEnvPrefix("MYAPP")
+			flags.SetAutoEnv(true)
+		},
+		SetPersistentFlags: func(flags *FlagSet) {
+			flags.BoolVar(&verbose, "verbose", false, "verbose output")
+		},
+	}
+
+	sub := &Command{
+		Name: "sub",
+		SetFlags: func(flags *FlagSet) {
+			flags.StringVar(&format, "format", "text", "output format")
+		},
+		Run: func(cmd *Command, args []string) error { return nil },
+	}
+
+	root.AddSubcommands(sub)
+
+	if err := root.execCommand([]string{"sub"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !verbose || format != "json" {
+		t.Errorf("unexpected values: verbose=%v, format=%v", verbose, format)
+	}
+
+	var b strings.Builder
+
+	if err := sub.renderHelp(&b, terminal{width: 120}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"output format (default: text; env: MYAPP_FORMAT)", "verbose output (env: MYAPP_VERBOSE)"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+		}
+	}
+}
+
+func TestCommand_AutoEnvSetAfterSubcommand(t *testing.T) {
+	helperDisableStdout(t)
+
+	t.Setenv("MYAPP_LISTEN_ADDR", ":9")
+	t.Setenv("MYAPP_MAX_CONNS", "5")
+
+	type config struct {
+		ListenAddr string `flag:"listen-addr" default:":80" usage:"Listen address"`
+	}
+
+	type autoConfig struct {
+		MaxConns int `default:"1" usage:"Max connections"`
+	}
+
+	root := &Command{Name: "root"}
+	sub := &Command{
+		Name: "sub",
+		Run:  func(cmd *Command, args []string) error { return nil },
+	}
+
+	root.AddSubcommands(sub)
+
+	// The flags of the subcommand are bound before the root sets the mode and the prefix.
+	cfg, autoCfg := &config{}, &autoConfig{}
+
+	if err := sub.BindConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sub.BindConfig(autoCfg, AutoNames()); err != nil {
+		t.Fatal(err)
+	}
+
+	root.Flags().SetEnvPrefix("MYAPP")
+	root.Flags().SetAutoEnv(true)
+
+	if err := root.execCommand([]string{"sub"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.ListenAddr != ":9" || autoCfg.MaxConns != 5 {
+		t.Errorf("unexpected values: listen-addr=%v, max-conns=%v", cfg.ListenAddr, autoCfg.MaxConns)
+	}
+
+	if source, _ := sub.Flags().Source("listen-addr"); source != SourceEnv {
+		t.Errorf("want := %v, got := %v", SourceEnv, source)
+	}
+}
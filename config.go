@@ -1,19 +1,40 @@
 package scotty
 
 import (
+	"encoding"
+	"flag"
 	"fmt"
 	"reflect"
 	"strconv"
-	"time"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Struct tag names for config binding.
 const (
-	tagFlag     = "flag"
-	tagEnv      = "env"
-	tagDefault  = "default"
-	tagUsage    = "usage"
-	tagRequired = "required"
+	tagFlag        = "flag"
+	tagEnv         = "env"
+	tagDefault     = "default"
+	tagUsage       = "usage"
+	tagRequired    = "required"
+	tagPlaceholder = "placeholder"
+	tagGroup       = "group"
+	tagHidden      = "hidden"
+	tagDeprecated  = "deprecated"
+	tagSep         = "sep"
+	tagDuplicates  = "duplicates"
+	tagOneOf       = "oneof"
+	tagIgnoreCase  = "ignorecase"
+	tagLayout      = "layout"
+	tagEncoding    = "encoding"
+	tagNegatable   = "negatable"
+	tagCount       = "count"
+	tagXor         = "xor"
+	tagTogether    = "together"
+	tagAnyOf       = "anyof"
+	tagPrefix      = "prefix"
+	tagSecret      = "secret"
 )
 
 // ConfigValidator holds logic of validation the config parameters.
@@ -49,8 +70,6 @@ func GetConfig[T any](cmd *Command) (*T, bool) {
 type requiredFieldInfo struct {
 	fieldName string
 	flagName  string
-	envName   string
-	fieldPtr  reflect.Value
 }
 
 // fieldOpts holds options for binding a struct field to a flag.
@@ -59,11 +78,47 @@ type fieldOpts struct {
 	envName    string
 	defaultVal string
 	usage      string
+	sep        rune
+	duplicates DuplicateKeyPolicy
+	enum       []string
+	ignoreCase bool
+	layout     string
+	encoding   string
+	count      bool
+}
+
+// BindOption configures the binding of a config struct to flags.
+type BindOption func(*bindOptions)
+
+// bindOptions holds the options of binding a config struct.
+type bindOptions struct {
+	// autoNames enables deriving flag and environment variable names from field names.
+	autoNames bool
+}
+
+// AutoNames derives the names of flags and environment variables missing
+// in the flag and env tags from the names of the struct fields: the MaxConns
+// field is bound to the -max-conns flag and the MAX_CONNS environment variable,
+// which takes the prefix set by FlagSet.SetEnvPrefix. Fields tagged flag:"-"
+// are skipped.
+func AutoNames() BindOption {
+	return func(o *bindOptions) { o.autoNames = true }
+}
+
+// newBindOptions applies the options of binding.
+func newBindOptions(options []BindOption) bindOptions {
+	var o bindOptions
+
+	for _, option := range options {
+		option(&o)
+	}
+
+	return o
 }
 
 // bindConfigToFlagSet uses reflection to bind struct fields to flags.
 // cfg must be a pointer to a struct.
-func bindConfigToFlagSet(f *FlagSet, cfg any) error {
+func bindConfigToFlagSet(f *FlagSet, cfg any, options bindOptions) error {
 	v := reflect.ValueOf(cfg)
 	if v.Kind() != reflect.Ptr {
 		return fmt.Errorf("config must be a pointer, got %T", cfg)
@@ -74,202 +129,504 @@ func bindConfigToFlagSet(f *FlagSet, cfg any) error {
 		return fmt.Errorf("config must be a pointer to struct, got pointer to %s", v.Kind())
 	}
 
+	b := &configBinder{
+		f:       f,
+		options: options,
+		indexes: make(map[constraintGroup]int),
+	}
+
+	if err := b.bindStruct(v, configScope{}); err != nil {
+		return err
+	}
+
+	f.constraints = append(f.constraints, b.constraints...)
+
+	return nil
+}
+
+// configBinder binds the fields of the config struct and its nested structs to flags.
+type configBinder struct {
+	f       *FlagSet
+	options bindOptions
+
+	// constraints holds the relationship constraints declared by struct tags
+	// and indexes holds their indexes by the kind and the group name.
+	constraints []flagConstraint
+	indexes     map[constraintGroup]int
+}
+
+// configScope holds the path of the nested struct in the config
+// and the prefixes of the names of the flags bound to its fields.
+type configScope struct {
+	// path holds the field path of the nested struct with the trailing dot, e.g. "DB.".
+	path string
+
+	// flagPrefix holds the prefix of the flag names, e.g. "db-".
+	flagPrefix string
+
+	// envPrefix holds the prefix of the environment variable names, e.g. "DB_".
+	envPrefix string
+}
+
+// bindStruct binds the fields of the struct to flags. The nested structs
+// are bound recursively, see nestedScope.
+func (b *configBinder) bindStruct(v reflect.Value, scope configScope) error {
 	t := v.Type()
 
 	for i := range t.NumField() {
 		field := t.Field(i)
 		fieldVal := v.Field(i)
 
+		if field.Tag.Get(tagFlag) == "-" {
+			continue // Skip excluded fields.
+		}
+
+		nested, ok := b.nestedScope(field, fieldVal, scope)
+		if ok {
+			if err := b.bindNested(field, fieldVal, nested); err != nil {
+				return err
+			}
+
+			continue
+		}
+
 		// Skip unexported fields.
 		if !fieldVal.CanSet() {
 			continue
 		}
 
-		flagName := field.Tag.Get(tagFlag)
-		if flagName == "" {
-			continue // Skip fields without flag tag.
+		if err := b.bindStructField(field, fieldVal, scope); err != nil {
+			return err
 		}
+	}
 
-		envName := field.Tag.Get(tagEnv)
-		defaultVal := field.Tag.Get(tagDefault)
-		usage := field.Tag.Get(tagUsage)
-		required := field.Tag.Get(tagRequired) == "true"
+	return nil
+}
 
-		opts := fieldOpts{
-			flagName:   flagName,
-			envName:    envName,
-			defaultVal: defaultVal,
-			usage:      usage,
-		}
+// nestedScope reports whether the field holds a nested struct and returns
+// its scope. Embedded structs are flattened, while the names of the flags
+// bound to the fields of other nested structs take the prefix from the prefix
+// tag or, with the AutoNames option, from the field name: the Host field
+// of the DB field tagged prefix:"db" is bound to the -db-host flag.
+func (b *configBinder) nestedScope(field reflect.StructField, fieldVal reflect.Value, scope configScope) (configScope, bool) {
+	if field.Tag.Get(tagFlag) != "" || !isNestedStruct(fieldVal) {
+		return configScope{}, false
+	}
 
-		if err := bindField(f, fieldVal, opts); err != nil {
-			return fmt.Errorf("binding field %s: %w", field.Name, err)
+	prefix := field.Tag.Get(tagPrefix)
+	if prefix == "" && !field.Anonymous {
+		if !b.options.autoNames || !field.IsExported() {
+			return configScope{}, false
 		}
 
-		if required {
-			f.requiredFields = append(f.requiredFields, requiredFieldInfo{
-				fieldName: field.Name,
-				flagName:  flagName,
-				envName:   envName,
-				fieldPtr:  fieldVal,
-			})
+		prefix = kebabCase(field.Name)
+	}
+
+	nested := scope
+	if !field.Anonymous {
+		nested.path += field.Name + "."
+	}
+
+	if prefix != "" {
+		nested.flagPrefix += prefix + "-"
+		nested.envPrefix += envCase(prefix) + "_"
+	}
+
+	return nested, true
+}
+
+// bindNested binds the fields of the nested struct allocating the pointer
+// to struct when it is nil. The nested struct implementing ConfigValidator
+// is validated after parsing together with the config.
+func (b *configBinder) bindNested(field reflect.StructField, fieldVal reflect.Value, scope configScope) error {
+	if fieldVal.Kind() == reflect.Ptr {
+		if fieldVal.IsNil() {
+			// The pointer to unexported embedded struct can't be allocated.
+			if !fieldVal.CanSet() {
+				return nil
+			}
+
+			fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
 		}
+
+		fieldVal = fieldVal.Elem()
+	}
+
+	if err := b.bindStruct(fieldVal, scope); err != nil {
+		return err
+	}
+
+	// The methods of embedded structs are promoted to the config itself.
+	if field.Anonymous || !fieldVal.CanAddr() {
+		return nil
+	}
+
+	if validator, ok := fieldVal.Addr().Interface().(ConfigValidator); ok {
+		b.f.validators = append(b.f.validators, nestedValidator{
+			path:      strings.TrimSuffix(scope.path, "."),
+			validator: validator,
+		})
 	}
 
 	return nil
 }
 
-// bindField binds a single struct field to a flag based on its type.
-//
-//nolint:cyclop,revive // Switch on types is inherently complex.
-func bindField(f *FlagSet, fieldVal reflect.Value, opts fieldOpts) error {
-	switch fieldVal.Kind() {
-	case reflect.String:
-		ptr, ok := fieldVal.Addr().Interface().(*string)
-		if !ok {
-			return fmt.Errorf("invalid type for string field")
-		}
-		if opts.envName != "" {
-			f.StringVarE(ptr, opts.flagName, opts.envName, opts.defaultVal, opts.usage)
-		} else {
-			f.StringVar(ptr, opts.flagName, opts.defaultVal, opts.usage)
-		}
+// bindStructField binds a single struct field to a flag using the struct tags.
+func (b *configBinder) bindStructField(field reflect.StructField, fieldVal reflect.Value, scope configScope) error {
+	f := b.f
+	fieldName := scope.path + field.Name
 
-	case reflect.Bool:
-		ptr, ok := fieldVal.Addr().Interface().(*bool)
-		if !ok {
-			return fmt.Errorf("invalid type for bool field")
+	flagName := field.Tag.Get(tagFlag)
+	envName := field.Tag.Get(tagEnv)
+
+	if flagName == "" {
+		if !b.options.autoNames {
+			return nil // Skip fields without flag tag.
 		}
-		parsed, err := strconv.ParseBool(opts.defaultVal)
-		def := tern(err == nil, parsed, false)
-		if opts.envName != "" {
-			f.BoolVarE(ptr, opts.flagName, opts.envName, def, opts.usage)
-		} else {
-			f.BoolVar(ptr, opts.flagName, def, opts.usage)
+
+		flagName = kebabCase(field.Name)
+	}
+
+	flagName = scope.flagPrefix + flagName
+
+	if envName != "" {
+		envName = scope.envPrefix + envName
+	}
+
+	opts := fieldOpts{
+		flagName:   flagName,
+		envName:    envName,
+		defaultVal: field.Tag.Get(tagDefault),
+		usage:      field.Tag.Get(tagUsage),
+		sep:        f.sliceSeparator(),
+		duplicates: f.duplicateKeys,
+		ignoreCase: f.enumIgnoreCase,
+		layout:     field.Tag.Get(tagLayout),
+		encoding:   field.Tag.Get(tagEncoding),
+		count:      field.Tag.Get(tagCount) == "true",
+	}
+
+	// String fields are bound to enum flags, while the oneof tag
+	// of other types is a validation rule checked after parsing.
+	if oneOf := field.Tag.Get(tagOneOf); oneOf != "" && indirectType(fieldVal.Type()).Kind() == reflect.String {
+		opts.enum = strings.Split(oneOf, ",")
+	}
+
+	if ignoreCase := field.Tag.Get(tagIgnoreCase); ignoreCase != "" {
+		opts.ignoreCase = ignoreCase == "true"
+	}
+
+	if sep := field.Tag.Get(tagSep); sep != "" {
+		opts.sep, _ = utf8.DecodeRuneInString(sep)
+		if !validSliceSeparator(opts.sep) {
+			return fmt.Errorf("binding field %s: invalid slice separator: %q", fieldName, sep)
 		}
+	}
 
-	case reflect.Int:
-		ptr, ok := fieldVal.Addr().Interface().(*int)
-		if !ok {
-			return fmt.Errorf("invalid type for int field")
+	if duplicates := field.Tag.Get(tagDuplicates); duplicates != "" {
+		policy, err := parseDuplicateKeyPolicy(duplicates)
+		if err != nil {
+			return fmt.Errorf("binding field %s: %w", fieldName, err)
 		}
-		parsed, err := strconv.Atoi(opts.defaultVal)
-		def := tern(err == nil, parsed, 0)
-		if opts.envName != "" {
-			f.IntVarE(ptr, opts.flagName, opts.envName, def, opts.usage)
-		} else {
-			f.IntVar(ptr, opts.flagName, def, opts.usage)
+
+		opts.duplicates = policy
+	}
+
+	if err := bindField(f, fieldVal, opts); err != nil {
+		return fmt.Errorf("binding field %s: %w", fieldName, err)
+	}
+
+	// The name is derived when parsing, so the prefix may be set after binding.
+	if envName == "" && b.options.autoNames {
+		f.metaFor(flagName).autoEnv = true
+	}
+
+	rules, err := fieldRules(field.Tag, fieldVal.Type())
+	if err != nil {
+		return fmt.Errorf("binding field %s: %w", fieldName, err)
+	}
+
+	if len(rules) > 0 {
+		f.validations = append(f.validations, fieldValidation{
+			fieldName: fieldName,
+			flagName:  flagName,
+			fieldVal:  fieldVal,
+			rules:     rules,
+		})
+
+		for _, rule := range rules {
+			f.metaFor(flagName).rules = append(f.metaFor(flagName).rules, rule.note)
 		}
+	}
 
-	case reflect.Int64:
-		if fieldVal.Type() == reflect.TypeOf(time.Duration(0)) {
-			ptr, ok := fieldVal.Addr().Interface().(*time.Duration)
-			if !ok {
-				return fmt.Errorf("invalid type for duration field")
-			}
-			parsed, err := time.ParseDuration(opts.defaultVal)
-			def := tern(err == nil, parsed, 0)
-			if opts.envName != "" {
-				f.DurationVarE(ptr, opts.flagName, opts.envName, def, opts.usage)
-			} else {
-				f.DurationVar(ptr, opts.flagName, def, opts.usage)
-			}
-		} else {
-			ptr, ok := fieldVal.Addr().Interface().(*int64)
-			if !ok {
-				return fmt.Errorf("invalid type for int64 field")
-			}
-			parsed, err := strconv.ParseInt(opts.defaultVal, 10, 64)
-			def := tern(err == nil, parsed, int64(0))
-			if opts.envName != "" {
-				f.Int64VarE(ptr, opts.flagName, opts.envName, def, opts.usage)
-			} else {
-				f.Int64Var(ptr, opts.flagName, def, opts.usage)
-			}
+	if field.Tag.Get(tagNegatable) == "true" {
+		if fieldVal.Kind() != reflect.Bool {
+			return fmt.Errorf("binding field %s: negatable tag requires bool field, got %s", fieldName, fieldVal.Type())
 		}
 
-	case reflect.Uint:
-		ptr, ok := fieldVal.Addr().Interface().(*uint)
+		f.MarkNegatable(flagName)
+	}
+
+	for _, group := range constraintGroups(field.Tag) {
+		i, ok := b.indexes[group]
 		if !ok {
-			return fmt.Errorf("invalid type for uint field")
+			i = len(b.constraints)
+			b.indexes[group] = i
+			b.constraints = append(b.constraints, flagConstraint{kind: group.kind})
 		}
-		parsed, err := strconv.ParseUint(opts.defaultVal, 10, 64)
-		def := tern(err == nil, uint(parsed), uint(0))
-		if opts.envName != "" {
-			f.UintVarE(ptr, opts.flagName, opts.envName, def, opts.usage)
-		} else {
-			f.UintVar(ptr, opts.flagName, def, opts.usage)
+
+		b.constraints[i].names = append(b.constraints[i].names, flagName)
+	}
+
+	if field.Tag.Get(tagSecret) == "true" {
+		// Only the Secret type keeps the value masked in fmt output of the struct.
+		if t := fieldVal.Type(); t != reflect.TypeFor[Secret]() && t != reflect.TypeFor[*Secret]() {
+			return fmt.Errorf("binding field %s: secret tag requires scotty.Secret field, got %s", fieldName, t)
 		}
 
-	case reflect.Uint64:
-		ptr, ok := fieldVal.Addr().Interface().(*uint64)
-		if !ok {
-			return fmt.Errorf("invalid type for uint64 field")
+		f.MarkSecret(flagName)
+	}
+
+	if placeholder := field.Tag.Get(tagPlaceholder); placeholder != "" {
+		f.metaFor(flagName).placeholder = placeholder
+	}
+
+	if group := field.Tag.Get(tagGroup); group != "" {
+		f.SetGroup(group, flagName)
+	}
+
+	if field.Tag.Get(tagHidden) == "true" {
+		f.MarkHidden(flagName)
+	}
+
+	if deprecated := field.Tag.Get(tagDeprecated); deprecated != "" {
+		f.MarkDeprecated(flagName, deprecated)
+	}
+
+	if field.Tag.Get(tagRequired) == "true" {
+		f.metaFor(flagName).required = true
+		f.requiredFields = append(f.requiredFields, requiredFieldInfo{
+			fieldName: fieldName,
+			flagName:  flagName,
+		})
+	}
+
+	return nil
+}
+
+// indirectType returns the type t points to, or t itself when it is not a pointer type.
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+
+	return t
+}
+
+// isNestedStruct reports whether the value is a struct or a pointer to struct
+// which is not bound to a single flag by a registered binder, flag.Value
+// or encoding.TextUnmarshaler.
+func isNestedStruct(v reflect.Value) bool {
+	t := v.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	if _, ok := lookupBinder(v.Type()); ok {
+		return false
+	}
+
+	ptr := reflect.PointerTo(t)
+
+	return !ptr.Implements(reflect.TypeFor[flag.Value]()) && !ptr.Implements(reflect.TypeFor[encoding.TextUnmarshaler]())
+}
+
+// constraintGroup identifies the relationship constraint declared by struct tags.
+type constraintGroup struct {
+	kind constraintKind
+	name string
+}
+
+// constraintGroups returns the relationship constraints the field belongs to
+// by the xor, together and anyof tags. Each tag holds comma-separated names
+// of groups, fields with the same group name form one constraint.
+func constraintGroups(tag reflect.StructTag) []constraintGroup {
+	var groups []constraintGroup
+
+	for _, kt := range []struct {
+		kind constraintKind
+		tag  string
+	}{
+		{constraintMutuallyExclusive, tagXor},
+		{constraintRequiredTogether, tagTogether},
+		{constraintOneRequired, tagAnyOf},
+	} {
+		value := tag.Get(kt.tag)
+		if value == "" {
+			continue
 		}
-		parsed, err := strconv.ParseUint(opts.defaultVal, 10, 64)
-		def := tern(err == nil, parsed, uint64(0))
-		if opts.envName != "" {
-			f.Uint64VarE(ptr, opts.flagName, opts.envName, def, opts.usage)
-		} else {
-			f.Uint64Var(ptr, opts.flagName, def, opts.usage)
+
+		for name := range strings.SplitSeq(value, ",") {
+			groups = append(groups, constraintGroup{kind: kt.kind, name: strings.TrimSpace(name)})
 		}
+	}
+
+	return groups
+}
+
+// bindField binds a single struct field to a flag based on its type
+// using the binder registered for the type.
+func bindField(f *FlagSet, fieldVal reflect.Value, opts fieldOpts) error {
+	if _, ok := lookupBinder(fieldVal.Type()); !ok && fieldVal.Kind() == reflect.Ptr {
+		return bindOptionalField(f, fieldVal, opts)
+	}
 
-	case reflect.Float64:
-		ptr, ok := fieldVal.Addr().Interface().(*float64)
+	if opts.enum != nil {
+		return bindEnumField(f, fieldVal, opts)
+	}
+
+	if opts.count {
+		ptr, ok := fieldVal.Addr().Interface().(*int)
 		if !ok {
-			return fmt.Errorf("invalid type for float64 field")
-		}
-		parsed, err := strconv.ParseFloat(opts.defaultVal, 64)
-		def := tern(err == nil, parsed, 0.0)
-		if opts.envName != "" {
-			f.Float64VarE(ptr, opts.flagName, opts.envName, def, opts.usage)
-		} else {
-			f.Float64Var(ptr, opts.flagName, def, opts.usage)
+			return fmt.Errorf("count tag requires int field, got %s", fieldVal.Type())
 		}
 
+		f.CountVarE(ptr, opts.flagName, opts.envName, parseDefault(opts.defaultVal, strconv.Atoi), opts.usage)
+
+		return nil
+	}
+
+	if binder, ok := lookupBinder(fieldVal.Type()); ok {
+		return binder(f, fieldVal.Addr().Interface(), opts)
+	}
+
+	switch ptr := fieldVal.Addr().Interface().(type) {
+	case flag.Value:
+		bindValueField(f, fieldVal, ptr, opts)
+
+	case encoding.TextUnmarshaler:
+		bindValueField(f, fieldVal, newTextValue(ptr), opts)
+
 	default:
-		return fmt.Errorf("unsupported field type: %s", fieldVal.Kind())
+		return fmt.Errorf("unsupported field type: %s", fieldVal.Type())
 	}
 
 	return nil
 }
 
-// validateRequiredFields checks that all required fields have non-zero values.
-func validateRequiredFields(fields []requiredFieldInfo) error {
-	for _, f := range fields {
-		if isZeroValue(f.fieldPtr) {
-			return &RequiredFieldError{
-				FieldName: f.fieldName,
-				FlagName:  f.flagName,
-				EnvName:   f.envName,
-			}
+// bindValueField binds the struct field to the flag of the value which sets
+// the field. The default value and the environment variable value are set
+// the same way as the flag value. A value which can't be set leaves the field
+// as it was before.
+func bindValueField(f *FlagSet, fieldVal reflect.Value, v flag.Value, opts fieldOpts) {
+	set := func(s string) error {
+		saved := reflect.New(fieldVal.Type()).Elem()
+		saved.Set(fieldVal)
+		fieldVal.SetZero()
+
+		if err := v.Set(s); err != nil {
+			fieldVal.Set(saved)
+			return err
 		}
+
+		return nil
+	}
+
+	if opts.defaultVal != "" {
+		//nolint:errcheck // The field keeps its value when the default value can't be set.
+		set(opts.defaultVal)
 	}
 
+	f.envVar(v, opts.flagName, opts.envName, opts.usage)
+	f.metaFor(opts.flagName).setEnv = set
+}
+
+// bindEnumField binds a string struct field to an enum flag with allowed
+// values from the field options. The default value must be allowed.
+func bindEnumField(f *FlagSet, fieldVal reflect.Value, opts fieldOpts) error {
+	ptr, ok := fieldVal.Addr().Interface().(*string)
+	if !ok {
+		return fmt.Errorf("oneof tag requires string field, got %s", fieldVal.Type())
+	}
+
+	if opts.defaultVal != "" {
+		def := newEnumValue(new(string), opts.enum, "", opts.ignoreCase)
+		if err := def.Set(opts.defaultVal); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+
+		opts.defaultVal = def.String()
+	}
+
+	f.enumVarE(newEnumValue(ptr, opts.enum, opts.defaultVal, opts.ignoreCase), opts.flagName, opts.envName, opts.usage)
+
 	return nil
 }
 
-// isZeroValue checks if a reflect.Value holds the zero value for its type.
-func isZeroValue(v reflect.Value) bool {
-	switch v.Kind() {
-	case reflect.String:
-		return v.String() == ""
-	case reflect.Bool:
-		return !v.Bool()
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return v.Int() == 0
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return v.Uint() == 0
-	case reflect.Float32, reflect.Float64:
-		return v.Float() == 0
-	default:
-		return false
+// kebabCase converts the Go identifier to kebab case: MaxConns
+// to max-conns, HTTPPort to http-port.
+func kebabCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			// A word starts after a lower case letter or a digit,
+			// or at the last upper case letter of an acronym.
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteRune('-')
+			}
+		}
+
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+// validateRequiredFields checks that all required fields have been provided
+// in the command line or by the environment variables and returns
+// RequiredFieldError for each missing one.
+func validateRequiredFields(f *FlagSet) []error {
+	var errs []error
+
+	for _, field := range f.requiredFields {
+		if !f.Changed(field.flagName) {
+			errs = append(errs, &RequiredFieldError{
+				FieldName: field.fieldName,
+				FlagName:  field.flagName,
+				EnvName:   f.envName(field.flagName),
+			})
+		}
 	}
+
+	return errs
 }
 
-// validateConfig calls Validate() if cfg implements ConfigValidator.
-func validateConfig(cfg any) error {
-	if validator, ok := cfg.(ConfigValidator); ok {
+// nestedValidator holds the nested struct of the config implementing ConfigValidator.
+type nestedValidator struct {
+	path      string
+	validator ConfigValidator
+}
+
+// validateConfig calls Validate() of the nested structs implementing ConfigValidator,
+// the innermost first, and then of the config itself if it implements ConfigValidator.
+func validateConfig(f *FlagSet) error {
+	for _, nested := range f.validators {
+		if err := nested.validator.Validate(); err != nil {
+			return fmt.Errorf("config validation failed: %s: %w", nested.path, err)
+		}
+	}
+
+	if validator, ok := f.config.(ConfigValidator); ok {
 		if err := validator.Validate(); err != nil {
 			return fmt.Errorf("config validation failed: %w", err)
 		}
@@ -2,7 +2,13 @@ package scotty
 
 import (
 	"errors"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 )
@@ -369,6 +375,33 @@ func TestGetConfig(t *testing.T) {
 	}
 }
 
+func TestBindConfig_HelpTags(t *testing.T) {
+	type config struct {
+		Addr   string `flag:"addr" placeholder:"ADDR" group:"Server" usage:"listen address"`
+		Listen string `flag:"listen" deprecated:"use -addr" usage:"listen address"`
+		Trace  bool   `flag:"trace" hidden:"true" usage:"trace internals"`
+	}
+
+	cmd := &Command{Name: "test"}
+	if err := cmd.BindConfig(&config{}); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	flags := cmd.Flags()
+
+	if m := flags.lookupMeta("addr"); m.placeholder != "ADDR" || m.group != "Server" {
+		t.Errorf("addr meta = %+v, want placeholder ADDR and group Server", m)
+	}
+
+	if m := flags.lookupMeta("listen"); m.deprecated != "use -addr" {
+		t.Errorf("listen deprecated = %q, want %q", m.deprecated, "use -addr")
+	}
+
+	if !flags.isHidden("trace") {
+		t.Error("trace should be hidden")
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsAt(s, substr, 0))
 }
@@ -386,3 +419,745 @@ func containsAt(s, substr string, start int) bool {
 
 	return false
 }
+
+func TestBindConfig_Slices(t *testing.T) {
+	type config struct {
+		Tags    []string        `flag:"tag" env:"TEST_TAGS" default:"a,b" usage:"Tags"`
+		Ports   []int           `flag:"port" sep:";" default:"80;443" usage:"Ports"`
+		Retries []time.Duration `flag:"retry" env:"TEST_RETRIES" usage:"Retries"`
+	}
+
+	t.Setenv("TEST_RETRIES", "1s,2s")
+
+	cfg := &config{}
+	cmd := &Command{Name: "test"}
+
+	if err := cmd.BindConfig(cfg); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	if err := cmd.Flags().Parse([]string{"-port", "8080;8443", "-port", "9090"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if want := []string{"a", "b"}; !reflect.DeepEqual(cfg.Tags, want) {
+		t.Errorf("Tags = %v, want %v", cfg.Tags, want)
+	}
+
+	if want := []int{8080, 8443, 9090}; !reflect.DeepEqual(cfg.Ports, want) {
+		t.Errorf("Ports = %v, want %v", cfg.Ports, want)
+	}
+
+	if want := []time.Duration{time.Second, 2 * time.Second}; !reflect.DeepEqual(cfg.Retries, want) {
+		t.Errorf("Retries = %v, want %v", cfg.Retries, want)
+	}
+
+	if err := cmd.BindConfig(&struct {
+		Bools []bool `flag:"bools"`
+	}{}); err == nil {
+		t.Error("expected error for unsupported slice type, got nil")
+	}
+}
+
+func TestBindConfig_Maps(t *testing.T) {
+	type config struct {
+		Labels   map[string]string        `flag:"label" env:"TEST_LABELS" default:"env=dev" usage:"Labels"`
+		Limits   map[string]int           `flag:"limit" duplicates:"error" usage:"Limits"`
+		Timeouts map[string]time.Duration `flag:"timeout" sep:";" default:"read=1s;write=2s" usage:"Timeouts"`
+	}
+
+	t.Setenv("TEST_LABELS", "env=prod,team=core")
+
+	cfg := &config{}
+	cmd := &Command{Name: "test"}
+
+	if err := cmd.BindConfig(cfg); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	if err := cmd.Flags().Parse([]string{"-limit", "cpu=2"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if want := map[string]string{"env": "prod", "team": "core"}; !reflect.DeepEqual(cfg.Labels, want) {
+		t.Errorf("Labels = %v, want %v", cfg.Labels, want)
+	}
+
+	if want := map[string]int{"cpu": 2}; !reflect.DeepEqual(cfg.Limits, want) {
+		t.Errorf("Limits = %v, want %v", cfg.Limits, want)
+	}
+
+	if want := map[string]time.Duration{"read": time.Second, "write": 2 * time.Second}; !reflect.DeepEqual(cfg.Timeouts, want) {
+		t.Errorf("Timeouts = %v, want %v", cfg.Timeouts, want)
+	}
+
+	if err := cmd.Flags().Set("limit", "cpu=4"); err == nil {
+		t.Error("expected duplicate key error, got nil")
+	}
+
+	if err := cmd.BindConfig(&struct {
+		Bad map[string]int `flag:"bad" duplicates:"never"`
+	}{}); err == nil {
+		t.Error("expected error for invalid duplicates policy, got nil")
+	}
+}
+
+func TestBindConfig_Enums(t *testing.T) {
+	type config struct {
+		Format string `flag:"format" env:"TEST_FORMAT" oneof:"json,yaml,table" default:"table" usage:"Format"`
+		Level  string `flag:"level" oneof:"debug,info" ignorecase:"true" default:"info" usage:"Level"`
+	}
+
+	t.Setenv("TEST_FORMAT", "yaml")
+
+	cfg := &config{}
+	cmd := &Command{Name: "test"}
+
+	if err := cmd.BindConfig(cfg); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	if err := cmd.Flags().Parse([]string{"-level", "DEBUG"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if cfg.Format != "yaml" {
+		t.Errorf("Format = %q, want %q", cfg.Format, "yaml")
+	}
+
+	if cfg.Level != "debug" {
+		t.Errorf("Level = %q, want %q", cfg.Level, "debug")
+	}
+
+	if err := cmd.Flags().Set("format", "xml"); err == nil {
+		t.Error("expected error for value not allowed, got nil")
+	}
+
+	if err := cmd.BindConfig(&struct {
+		Format string `flag:"format2" oneof:"json,yaml" default:"xml"`
+	}{}); err == nil {
+		t.Error("expected error for default not allowed, got nil")
+	}
+
+	if err := cmd.BindConfig(&struct {
+		Formats []string `flag:"formats" oneof:"json,yaml"`
+	}{}); err == nil {
+		t.Error("expected error for slice field, got nil")
+	}
+}
+
+type testCSV []string
+
+func (c *testCSV) Set(s string) error {
+	if s == "" {
+		return errors.New("empty value")
+	}
+
+	*c = strings.Split(s, ",")
+
+	return nil
+}
+
+func (c *testCSV) String() string { return strings.Join(*c, ",") }
+
+func TestBindConfig_TextAndFlagValues(t *testing.T) {
+	type config struct {
+		IP      net.IP    `flag:"ip" env:"TEST_IP" default:"127.0.0.1" usage:"IP"`
+		Since   time.Time `flag:"since" env:"TEST_SINCE" usage:"Since"`
+		Hosts   testCSV   `flag:"hosts" env:"TEST_HOSTS" default:"a,b" usage:"Hosts"`
+		Backups testCSV   `flag:"backups" default:"x" usage:"Backups"`
+	}
+
+	t.Setenv("TEST_SINCE", "not a time")
+	t.Setenv("TEST_HOSTS", "c,d")
+
+	cfg := &config{}
+	cmd := &Command{Name: "test"}
+
+	if err := cmd.BindConfig(cfg); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	if got := cmd.Flags().Lookup("ip").DefValue; got != "127.0.0.1" {
+		t.Errorf("DefValue = %q, want %q", got, "127.0.0.1")
+	}
+
+	if err := cmd.Flags().Parse([]string{"-ip", "10.0.0.1", "-backups", "y,z"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if want := net.ParseIP("10.0.0.1"); !cfg.IP.Equal(want) {
+		t.Errorf("IP = %v, want %v", cfg.IP, want)
+	}
+
+	if !cfg.Since.IsZero() {
+		t.Errorf("Since = %v, want zero time", cfg.Since)
+	}
+
+	if want := (testCSV{"c", "d"}); !reflect.DeepEqual(cfg.Hosts, want) {
+		t.Errorf("Hosts = %v, want %v", cfg.Hosts, want)
+	}
+
+	if want := (testCSV{"y", "z"}); !reflect.DeepEqual(cfg.Backups, want) {
+		t.Errorf("Backups = %v, want %v", cfg.Backups, want)
+	}
+
+	if err := cmd.Flags().Set("ip", "not an ip"); err == nil {
+		t.Error("expected error for invalid IP, got nil")
+	}
+
+	if err := cmd.BindConfig(&struct {
+		Ch chan int `flag:"ch"`
+	}{}); err == nil {
+		t.Error("expected error for unsupported type, got nil")
+	}
+}
+
+func TestBindConfig_WiderTypes(t *testing.T) {
+	type config struct {
+		Small   int8           `flag:"small" default:"-5"`
+		Medium  int16          `flag:"medium" env:"TEST_MEDIUM" default:"7"`
+		Large   int32          `flag:"large"`
+		Byte    uint8          `flag:"byte" default:"300"`
+		Word    uint16         `flag:"word"`
+		Dword   uint32         `flag:"dword" default:"42"`
+		Ratio   float32        `flag:"ratio" default:"0.5"`
+		Since   time.Time      `flag:"since" layout:"2006-01-02" default:"2024-01-02"`
+		Until   time.Time      `flag:"until"`
+		Server  *url.URL       `flag:"server" default:"https://example.com/api"`
+		IP      netip.Addr     `flag:"ip" env:"TEST_IP"`
+		Listen  netip.AddrPort `flag:"listen" default:"127.0.0.1:8080"`
+		Network netip.Prefix   `flag:"network" default:"10.0.0.0/8"`
+		Match   *regexp.Regexp `flag:"match" default:"^a+$"`
+		Mode    os.FileMode    `flag:"mode" default:"0644"`
+		Key     []byte         `flag:"key" default:"c2VjcmV0"`
+		Salt    []byte         `flag:"salt" encoding:"hex" default:"cafe"`
+	}
+
+	t.Setenv("TEST_MEDIUM", "40000")
+	t.Setenv("TEST_IP", "::1")
+
+	cfg := &config{}
+	cmd := &Command{Name: "test"}
+
+	if err := cmd.BindConfig(cfg); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	args := []string{"-large", "-2147483648", "-word", "65535", "-until", "2024-05-06T07:08:09Z", "-mode", "0o600"}
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if cfg.Small != -5 || cfg.Medium != 7 || cfg.Large != -2147483648 {
+		t.Errorf("signed = %v %v %v, want -5 7 -2147483648", cfg.Small, cfg.Medium, cfg.Large)
+	}
+
+	if cfg.Byte != 0 || cfg.Word != 65535 || cfg.Dword != 42 || cfg.Ratio != 0.5 {
+		t.Errorf("unsigned = %v %v %v %v, want 0 65535 42 0.5", cfg.Byte, cfg.Word, cfg.Dword, cfg.Ratio)
+	}
+
+	if want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC); !cfg.Since.Equal(want) {
+		t.Errorf("Since = %v, want %v", cfg.Since, want)
+	}
+
+	if want := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC); !cfg.Until.Equal(want) {
+		t.Errorf("Until = %v, want %v", cfg.Until, want)
+	}
+
+	if cfg.Server == nil || cfg.Server.Host != "example.com" {
+		t.Errorf("Server = %v, want https://example.com/api", cfg.Server)
+	}
+
+	if cfg.IP != netip.IPv6Loopback() {
+		t.Errorf("IP = %v, want ::1", cfg.IP)
+	}
+
+	if cfg.Listen.Port() != 8080 || cfg.Network.Bits() != 8 {
+		t.Errorf("Listen = %v, Network = %v", cfg.Listen, cfg.Network)
+	}
+
+	if cfg.Match == nil || !cfg.Match.MatchString("aaa") {
+		t.Errorf("Match = %v, want ^a+$", cfg.Match)
+	}
+
+	if cfg.Mode != 0o600 {
+		t.Errorf("Mode = %v, want %v", cfg.Mode, os.FileMode(0o600))
+	}
+
+	if string(cfg.Key) != "secret" || !reflect.DeepEqual(cfg.Salt, []byte{0xca, 0xfe}) {
+		t.Errorf("Key = %q, Salt = %x", cfg.Key, cfg.Salt)
+	}
+
+	flags := cmd.Flags()
+
+	for name, want := range map[string]string{"mode": "0644", "since": "2024-01-02", "salt": "cafe", "until": ""} {
+		if got := flags.Lookup(name).DefValue; got != want {
+			t.Errorf("DefValue of %s = %q, want %q", name, got, want)
+		}
+	}
+
+	for name, value := range map[string]string{"small": "128", "match": "(", "mode": "1777", "salt": "xyz"} {
+		if err := flags.Set(name, value); err == nil {
+			t.Errorf("expected error for -%s=%s, got nil", name, value)
+		}
+	}
+
+	if err := cmd.BindConfig(&struct {
+		Data []byte `flag:"data" encoding:"base32"`
+	}{}); err == nil {
+		t.Error("expected error for invalid bytes encoding, got nil")
+	}
+}
+
+func TestBindConfig_ByteSize(t *testing.T) {
+	type config struct {
+		Upload ByteSize `flag:"max-upload" env:"TEST_MAX_UPLOAD" default:"10MB" usage:"Max upload size"`
+		Cache  ByteSize `flag:"cache" default:"512KiB" usage:"Cache size"`
+	}
+
+	t.Setenv("TEST_MAX_UPLOAD", "1.5GiB")
+
+	cfg := &config{}
+	cmd := &Command{Name: "test"}
+
+	if err := cmd.BindConfig(cfg); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	if err := cmd.Flags().Parse(nil); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if cfg.Upload != 1536*MiB {
+		t.Errorf("Upload = %v, want %v", cfg.Upload, 1536*MiB)
+	}
+
+	if cfg.Cache != 512*KiB {
+		t.Errorf("Cache = %v, want %v", cfg.Cache, 512*KiB)
+	}
+
+	if got := cmd.Flags().Lookup("cache").DefValue; got != "512KiB" {
+		t.Errorf("DefValue = %q, want %q", got, "512KiB")
+	}
+}
+
+func TestBindConfig_CountAndNegatable(t *testing.T) {
+	type config struct {
+		Verbose int  `flag:"v" env:"TEST_VERBOSE" count:"true" usage:"Verbosity"`
+		Cache   bool `flag:"cache" default:"true" negatable:"true" usage:"Use cache"`
+	}
+
+	cfg := &config{}
+	cmd := &Command{Name: "test"}
+
+	if err := cmd.BindConfig(cfg); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	if err := cmd.Flags().Parse([]string{"-vv", "-no-cache"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if cfg.Verbose != 2 {
+		t.Errorf("Verbose = %v, want %v", cfg.Verbose, 2)
+	}
+
+	if cfg.Cache {
+		t.Error("Cache = true, want false")
+	}
+
+	if err := cmd.BindConfig(&struct {
+		Name string `flag:"name" negatable:"true"`
+	}{}); err == nil {
+		t.Error("expected error for negatable non-bool field, got nil")
+	}
+
+	if err := cmd.BindConfig(&struct {
+		Level string `flag:"level" count:"true"`
+	}{}); err == nil {
+		t.Error("expected error for count non-int field, got nil")
+	}
+}
+
+func TestBindConfig_AutoNames(t *testing.T) {
+	type config struct {
+		MaxConns    int           `default:"10" usage:"Max connections"`
+		HTTPPort    int           `default:"8080"`
+		ReadTimeout time.Duration `env:"TEST_READ_TIMEOUT"`
+		Host        string        `flag:"hostname"`
+		Internal    string        `flag:"-"`
+	}
+
+	t.Setenv("MYAPP_MAX_CONNS", "20")
+	t.Setenv("TEST_READ_TIMEOUT", "5s")
+	t.Setenv("MYAPP_HOSTNAME", "example.com")
+
+	cfg := &config{}
+	cmd := &Command{Name: "test"}
+	cmd.Flags().SetEnvPrefix("MYAPP")
+
+	if err := cmd.BindConfig(cfg, AutoNames()); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	if err := cmd.Flags().Parse([]string{"-http-port=9090"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if cfg.MaxConns != 20 || cfg.HTTPPort != 9090 || cfg.ReadTimeout != 5*time.Second || cfg.Host != "example.com" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	for _, name := range []string{"Internal", "internal", "-"} {
+		if cmd.Flags().Lookup(name) != nil {
+			t.Errorf("expected flag %q not to be defined", name)
+		}
+	}
+
+	if got := cmd.Flags().envName("max-conns"); got != "MYAPP_MAX_CONNS" {
+		t.Errorf("env = %q, want %q", got, "MYAPP_MAX_CONNS")
+	}
+
+	// Fields without tags are skipped without the option.
+	if err := (&Command{Name: "test"}).BindConfig(&config{}); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+}
+
+func Test_kebabCase(t *testing.T) {
+	tests := map[string]string{
+		"Host":        "host",
+		"MaxConns":    "max-conns",
+		"HTTPPort":    "http-port",
+		"ID":          "id",
+		"TLSCertFile": "tls-cert-file",
+		"Port2":       "port2",
+		"V2Endpoint":  "v2-endpoint",
+	}
+
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := kebabCase(name); got != want {
+				t.Errorf("kebabCase(%q) = %q, want %q", name, got, want)
+			}
+		})
+	}
+}
+
+type testDBConfig struct {
+	Host string `flag:"host" env:"HOST" required:"true" usage:"Database host"`
+	Port int    `flag:"port" env:"PORT" default:"5432" usage:"Database port"`
+	Pool struct {
+		Size int `flag:"size" default:"10" usage:"Pool size"`
+	} `prefix:"pool"`
+}
+
+func (c *testDBConfig) Validate() error {
+	if c.Port == 0 {
+		return errors.New("port must not be zero")
+	}
+
+	return nil
+}
+
+type testCommonConfig struct {
+	Verbose bool `flag:"verbose" usage:"Verbose output"`
+}
+
+type testCacheConfig struct {
+	Addr string `flag:"addr" env:"ADDR" usage:"Cache address"`
+}
+
+func TestBindConfig_Nested(t *testing.T) {
+	type config struct {
+		testCommonConfig
+
+		DB    testDBConfig     `prefix:"db"`
+		Cache *testCacheConfig `prefix:"cache"`
+		Other testCacheConfig
+	}
+
+	newCommand := func(cfg *config) *Command {
+		cmd := &Command{
+			Name: "test",
+			Run:  func(cmd *Command, args []string) error { return nil },
+		}
+
+		if err := cmd.BindConfig(cfg); err != nil {
+			t.Fatalf("BindConfig failed: %v", err)
+		}
+
+		return cmd
+	}
+
+	t.Run("Names", func(t *testing.T) {
+		helperDisableStdout(t)
+
+		t.Setenv("DB_HOST", "db.local")
+		t.Setenv("CACHE_ADDR", "cache.local:6379")
+
+		cfg := &config{}
+
+		if err := newCommand(cfg).execCommand([]string{"-verbose", "-db-pool-size=20"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !cfg.Verbose || cfg.DB.Host != "db.local" || cfg.DB.Port != 5432 || cfg.DB.Pool.Size != 20 {
+			t.Errorf("unexpected config: %+v", cfg)
+		}
+
+		if cfg.Cache == nil || cfg.Cache.Addr != "cache.local:6379" {
+			t.Errorf("unexpected cache config: %+v", cfg.Cache)
+		}
+	})
+
+	t.Run("NestedWithoutPrefixIsSkipped", func(t *testing.T) {
+		cmd := newCommand(&config{})
+
+		if cmd.Flags().Lookup("addr") != nil {
+			t.Error("expected the nested struct without prefix not to be bound")
+		}
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		helperDisableStdout(t)
+
+		err := newCommand(&config{}).execCommand(nil)
+
+		var reqErr *RequiredFieldError
+		if !errors.As(err, &reqErr) {
+			t.Fatalf("expected RequiredFieldError, got %v", err)
+		}
+
+		if reqErr.FieldName != "DB.Host" || reqErr.FlagName != "db-host" || reqErr.EnvName != "DB_HOST" {
+			t.Errorf("unexpected error details: %+v", reqErr)
+		}
+	})
+
+	t.Run("Validator", func(t *testing.T) {
+		helperDisableStdout(t)
+
+		err := newCommand(&config{}).execCommand([]string{"-db-host=db.local", "-db-port=0"})
+		if err == nil || !strings.Contains(err.Error(), "config validation failed: DB: port must not be zero") {
+			t.Errorf("expected validation error of the nested struct, got %v", err)
+		}
+	})
+
+	t.Run("AutoNames", func(t *testing.T) {
+		type autoConfig struct {
+			Database struct {
+				MaxConns int `default:"5"`
+			}
+		}
+
+		t.Setenv("DATABASE_MAX_CONNS", "7")
+
+		cfg := &autoConfig{}
+		cmd := &Command{Name: "test"}
+
+		if err := cmd.BindConfig(cfg, AutoNames()); err != nil {
+			t.Fatalf("BindConfig failed: %v", err)
+		}
+
+		if err := cmd.Flags().Parse(nil); err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+
+		if cmd.Flags().Lookup("database-max-conns") == nil || cfg.Database.MaxConns != 7 {
+			t.Errorf("unexpected config: %+v", cfg)
+		}
+	})
+}
+
+func TestBindConfig_Optional(t *testing.T) {
+	type config struct {
+		Port    *int           `flag:"port" env:"TEST_PORT" usage:"Port"`
+		Debug   *bool          `flag:"debug" usage:"Debug mode"`
+		Timeout *time.Duration `flag:"timeout" env:"TEST_TIMEOUT" usage:"Timeout"`
+		Host    *string        `flag:"host" default:"localhost" usage:"Host"`
+		Level   *string        `flag:"level" oneof:"debug,info" usage:"Level"`
+	}
+
+	newConfig := func(t *testing.T, args []string) (*config, *Command) {
+		t.Helper()
+
+		cfg := &config{}
+		cmd := &Command{Name: "test"}
+
+		if err := cmd.BindConfig(cfg); err != nil {
+			t.Fatalf("BindConfig failed: %v", err)
+		}
+
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+
+		return cfg, cmd
+	}
+
+	t.Run("NotProvided", func(t *testing.T) {
+		cfg, _ := newConfig(t, nil)
+
+		if cfg.Port != nil || cfg.Debug != nil || cfg.Timeout != nil || cfg.Level != nil {
+			t.Errorf("expected nil fields, got %+v", cfg)
+		}
+
+		if cfg.Host == nil || *cfg.Host != "localhost" {
+			t.Errorf("expected default host, got %v", cfg.Host)
+		}
+	})
+
+	t.Run("Provided", func(t *testing.T) {
+		t.Setenv("TEST_TIMEOUT", "5s")
+
+		cfg, _ := newConfig(t, []string{"-port=0", "-debug", "-level=info"})
+
+		if cfg.Port == nil || *cfg.Port != 0 {
+			t.Errorf("expected explicit zero port, got %v", cfg.Port)
+		}
+
+		if cfg.Debug == nil || !*cfg.Debug {
+			t.Errorf("expected debug, got %v", cfg.Debug)
+		}
+
+		if cfg.Timeout == nil || *cfg.Timeout != 5*time.Second {
+			t.Errorf("expected timeout from env, got %v", cfg.Timeout)
+		}
+
+		if cfg.Level == nil || *cfg.Level != "info" {
+			t.Errorf("expected level, got %v", cfg.Level)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		cmd := &Command{Name: "test"}
+
+		if err := cmd.BindConfig(&config{}); err != nil {
+			t.Fatalf("BindConfig failed: %v", err)
+		}
+
+		if err := cmd.Flags().Set("level", "trace"); err == nil {
+			t.Error("expected error for value which is not allowed, got nil")
+		}
+	})
+
+	t.Run("Help", func(t *testing.T) {
+		_, cmd := newConfig(t, nil)
+
+		var b strings.Builder
+
+		if err := cmd.renderHelp(&b, terminal{width: 120}); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, want := range []string{"-port int", "Port (optional; env: TEST_PORT)", "-timeout duration", "Debug mode (optional)", "Host (default: localhost)\n"} {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+			}
+		}
+	})
+}
+
+func TestCommand_PersistentOptional(t *testing.T) {
+	type config struct {
+		Name *string `flag:"name" usage:"Name"`
+	}
+
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"NotProvided": {args: []string{"sub"}},
+		"Parent":      {args: []string{"-name", "a", "sub"}, want: "a"},
+		"Subcommand":  {args: []string{"sub", "-name", "b"}, want: "b"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			helperDisableStdout(t)
+
+			cfg := &config{}
+
+			root := &Command{
+				Name: "app",
+				SetPersistentFlags: func(flags *FlagSet) {
+					if err := flags.BindConfig(cfg); err != nil {
+						t.Fatal(err)
+					}
+				},
+			}
+
+			root.AddSubcommands(&Command{
+				Name: "sub",
+				Run:  func(cmd *Command, args []string) error { return nil },
+			})
+
+			if err := root.execCommand(tc.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.want == "" {
+				if cfg.Name != nil {
+					t.Errorf("want nil, got := %q", *cfg.Name)
+				}
+
+				return
+			}
+
+			if cfg.Name == nil || *cfg.Name != tc.want {
+				t.Errorf("want := %q, got := %v", tc.want, cfg.Name)
+			}
+		})
+	}
+}
+
+func TestBindConfig_AllErrors(t *testing.T) {
+	helperDisableStdout(t)
+
+	type config struct {
+		Host    string `flag:"host" env:"TEST_HOST" required:"true" usage:"Host"`
+		Token   string `flag:"token" env:"TEST_TOKEN" required:"true" usage:"Token"`
+		Port    int    `flag:"port" env:"TEST_PORT" default:"8080" usage:"Port"`
+		Workers int    `flag:"workers" min:"1" usage:"Workers"`
+		File    string `flag:"file" xor:"source" usage:"File"`
+		URL     string `flag:"url" xor:"source" usage:"URL"`
+	}
+
+	t.Setenv("TEST_PORT", "80a")
+
+	cmd := &Command{
+		Name: "test",
+		SetFlags: func(flags *FlagSet) {
+			flags.SetStrictEnv(true)
+		},
+		Run: func(cmd *Command, args []string) error { return nil },
+	}
+
+	if err := cmd.BindConfig(&config{}); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	err := cmd.execCommand([]string{"-workers=0", "-file=a", "-url=b"})
+
+	var configErrs ConfigErrors
+	if !errors.As(err, &configErrs) {
+		t.Fatalf("expected ConfigErrors, got %v", err)
+	}
+
+	if len(configErrs) != 5 {
+		t.Fatalf("expected 5 errors, got %d: %v", len(configErrs), err)
+	}
+
+	for _, want := range []string{
+		`invalid environment variable value "80a": parse error (flag: -port, env: TEST_PORT)`,
+		"Host: required field not set (flag: -host, env: TEST_HOST)",
+		"Token: required field not set (flag: -token, env: TEST_TOKEN)",
+		"Workers: value must be at least 1 (flag: -workers)",
+		"flags are mutually exclusive: -file, -url (set: -file, -url)",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing := %q, got := %q", want, err.Error())
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package scotty
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// constraintKind defines the relationship between the flags of a constraint.
+type constraintKind int
+
+const (
+	// constraintMutuallyExclusive allows at most one of the flags to be set.
+	constraintMutuallyExclusive constraintKind = iota
+
+	// constraintRequiredTogether requires either all or none of the flags to be set.
+	constraintRequiredTogether
+
+	// constraintOneRequired requires at least one of the flags to be set.
+	constraintOneRequired
+)
+
+// flagConstraint holds the relationship between the named flags.
+type flagConstraint struct {
+	kind  constraintKind
+	names []string
+}
+
+// MarkMutuallyExclusive allows at most one of the named flags to be set.
+// The constraint is checked after parsing, setting several of the flags
+// makes the command fail with FlagConstraintError. Panics if a flag is not defined.
+func (f *FlagSet) MarkMutuallyExclusive(names ...string) {
+	f.addConstraint(constraintMutuallyExclusive, names)
+}
+
+// MarkRequiredTogether requires the named flags to be set together:
+// either all of them or none. The constraint is checked after parsing,
+// setting only some of the flags makes the command fail with FlagConstraintError.
+// Panics if a flag is not defined.
+func (f *FlagSet) MarkRequiredTogether(names ...string) {
+	f.addConstraint(constraintRequiredTogether, names)
+}
+
+// MarkOneRequired requires at least one of the named flags to be set.
+// Combined with MarkMutuallyExclusive it requires exactly one of them.
+// The constraint is checked after parsing, setting none of the flags
+// makes the command fail with FlagConstraintError. Panics if a flag is not defined.
+func (f *FlagSet) MarkOneRequired(names ...string) {
+	f.addConstraint(constraintOneRequired, names)
+}
+
+// addConstraint adds the constraint of the named flags. Panics if a flag is not defined.
+func (f *FlagSet) addConstraint(kind constraintKind, names []string) {
+	for _, name := range names {
+		if f.Lookup(name) == nil {
+			panic(fmt.Errorf("flag '%s' is not defined", name))
+		}
+	}
+
+	f.constraints = append(f.constraints, flagConstraint{kind: kind, names: names})
+}
+
+// validateConstraints checks the relationship constraints of the flags
+// and returns FlagConstraintError describing each violated constraint.
+func (f *FlagSet) validateConstraints() []error {
+	var errs []error
+
+	for _, c := range f.constraints {
+		var set []string
+
+		for _, name := range c.names {
+			if f.Changed(name) {
+				set = append(set, name)
+			}
+		}
+
+		violated := false
+
+		switch c.kind {
+		case constraintMutuallyExclusive:
+			violated = len(set) > 1
+
+		case constraintRequiredTogether:
+			violated = len(set) > 0 && len(set) < len(c.names)
+
+		case constraintOneRequired:
+			violated = len(set) == 0
+		}
+
+		if violated {
+			errs = append(errs, &FlagConstraintError{Err: c.kind.err(), Flags: c.names, Set: set})
+		}
+	}
+
+	return errs
+}
+
+// constraintNotes returns the descriptions of the constraints
+// involving the named flag to show in the help output.
+func (f *FlagSet) constraintNotes(name string) []string {
+	var notes []string
+
+	for _, c := range f.constraints {
+		if !slices.Contains(c.names, name) {
+			continue
+		}
+
+		others := make([]string, 0, len(c.names)-1)
+
+		for _, other := range c.names {
+			if other != name {
+				others = append(others, "-"+other)
+			}
+		}
+
+		switch c.kind {
+		case constraintMutuallyExclusive:
+			notes = append(notes, "conflicts with "+strings.Join(others, ", "))
+
+		case constraintRequiredTogether:
+			notes = append(notes, "requires "+strings.Join(others, ", "))
+
+		case constraintOneRequired:
+			if len(others) == 0 {
+				notes = append(notes, "required")
+				continue
+			}
+
+			notes = append(notes, "required unless "+strings.Join(others, " or ")+" is set")
+		}
+	}
+
+	return notes
+}
+
+// err returns the sentinel error of the constraint kind.
+func (k constraintKind) err() Error {
+	switch k {
+	case constraintRequiredTogether:
+		return ErrRequiredTogether
+
+	case constraintOneRequired:
+		return ErrOneRequired
+
+	default:
+		return ErrMutuallyExclusive
+	}
+}
+
+// String returns the name of the constraint kind used in the spec.
+func (k constraintKind) String() string {
+	switch k {
+	case constraintRequiredTogether:
+		return "required_together"
+
+	case constraintOneRequired:
+		return "one_required"
+
+	default:
+		return "mutually_exclusive"
+	}
+}
@@ -0,0 +1,136 @@
+package scotty
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlagSet_validateConstraints(t *testing.T) {
+	type tcase struct {
+		mark    func(f *FlagSet)
+		env     string
+		args    []string
+		wantErr error
+		wantSet []string
+	}
+
+	exclusive := func(f *FlagSet) { f.MarkMutuallyExclusive("file", "url") }
+	together := func(f *FlagSet) { f.MarkRequiredTogether("cert", "key") }
+	oneRequired := func(f *FlagSet) { f.MarkOneRequired("file", "url") }
+
+	tests := map[string]tcase{
+		"Exclusive none":     {mark: exclusive},
+		"Exclusive one":      {mark: exclusive, args: []string{"-file", "a"}},
+		"Exclusive both":     {mark: exclusive, args: []string{"-file", "a", "-url", "b"}, wantErr: ErrMutuallyExclusive, wantSet: []string{"file", "url"}},
+		"Exclusive env":      {mark: exclusive, env: "b", args: []string{"-file", "a"}, wantErr: ErrMutuallyExclusive, wantSet: []string{"file", "url"}},
+		"Together none":      {mark: together},
+		"Together all":       {mark: together, args: []string{"-cert", "a", "-key", "b"}},
+		"Together some":      {mark: together, args: []string{"-cert", "a"}, wantErr: ErrRequiredTogether, wantSet: []string{"cert"}},
+		"One required none":  {mark: oneRequired, wantErr: ErrOneRequired},
+		"One required flag":  {mark: oneRequired, args: []string{"-url", "b"}},
+		"One required env":   {mark: oneRequired, env: "b"},
+		"Exactly one both":   {mark: func(f *FlagSet) { exclusive(f); oneRequired(f) }, args: []string{"-file=a", "-url=b"}, wantErr: ErrMutuallyExclusive, wantSet: []string{"file", "url"}},
+		"Exactly one single": {mark: func(f *FlagSet) { exclusive(f); oneRequired(f) }, args: []string{"-file=a"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TEST_URL", tc.env)
+
+			f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+
+			var file, url, cert, key string
+
+			f.StringVar(&file, "file", "", "")
+			f.StringVarE(&url, "url", "TEST_URL", "", "")
+			f.StringVar(&cert, "cert", "", "")
+			f.StringVar(&key, "key", "", "")
+			tc.mark(f)
+
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatal(err)
+			}
+
+			err := errors.Join(f.validateConstraints()...)
+			if !errors.Is(err, tc.wantErr) || (err == nil) != (tc.wantErr == nil) {
+				t.Fatalf("want error := %v, got := %v", tc.wantErr, err)
+			}
+
+			var constraintErr *FlagConstraintError
+			if errors.As(err, &constraintErr) && !reflect.DeepEqual(constraintErr.Set, tc.wantSet) {
+				t.Errorf("want set := %v, got := %v", tc.wantSet, constraintErr.Set)
+			}
+		})
+	}
+}
+
+func TestFlagSet_MarkConstraintUndefined(t *testing.T) {
+	tests := map[string]func(f *FlagSet, names ...string){
+		"Exclusive":   (*FlagSet).MarkMutuallyExclusive,
+		"Together":    (*FlagSet).MarkRequiredTogether,
+		"OneRequired": (*FlagSet).MarkOneRequired,
+	}
+
+	for name, mark := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer helperCatchPanic(t, fmt.Errorf("flag '%s' is not defined", "ulr"))
+
+			f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+			f.String("file", "", "")
+			f.String("url", "", "")
+
+			mark(f, "file", "ulr")
+		})
+	}
+}
+
+func TestCommand_FlagConstraints(t *testing.T) {
+	type config struct {
+		File string `flag:"file" xor:"source" anyof:"source" usage:"Read from file"`
+		URL  string `flag:"url" xor:"source" anyof:"source" usage:"Read from URL"`
+		Cert string `flag:"cert" together:"tls" usage:"TLS certificate"`
+		Key  string `flag:"key" together:"tls" usage:"TLS key"`
+	}
+
+	cmd := &Command{Name: "app", Run: func(*Command, []string) error { return nil }}
+	if err := cmd.BindConfig(&config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd.Flags().SetOutput(discard{})
+
+	if err := cmd.execCommand([]string{"-file", "a", "-cert", "c"}); !errors.Is(err, ErrRequiredTogether) {
+		t.Errorf("want := %v, got := %v", ErrRequiredTogether, err)
+	}
+
+	var b strings.Builder
+
+	if err := cmd.renderHelp(&b, terminal{width: 200}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"Read from file (conflicts with -url; required unless -url is set)",
+		"TLS certificate (requires -key)",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+		}
+	}
+
+	spec := commandSpec(cmd)
+
+	want := []ConstraintSpec{
+		{Kind: "mutually_exclusive", Flags: []string{"file", "url"}},
+		{Kind: "one_required", Flags: []string{"file", "url"}},
+		{Kind: "required_together", Flags: []string{"cert", "key"}},
+	}
+
+	if !reflect.DeepEqual(spec.Constraints, want) {
+		t.Errorf("want := %v, got := %v", want, spec.Constraints)
+	}
+}
@@ -0,0 +1,170 @@
+package scotty
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CountVar defines a counter flag with specified name, default value, and usage string.
+// The argument p points to an int variable in which to store the value of the flag.
+// Each occurrence of the flag increments the counter: -v -v -v and -vvv both give 3.
+// The counter can also be set explicitly: -v=2.
+func (f *FlagSet) CountVar(p *int, name string, value int, usage string) {
+	f.CountVarE(p, name, "", value, usage)
+}
+
+// CountVarE defines a counter flag and environment variable with specified name, default value, and usage string.
+// The argument p points to an int variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+// The environment variable holds the number, e.g. VERBOSITY=2, and occurrences of the flag count from zero.
+func (f *FlagSet) CountVarE(p *int, flagName, envName string, value int, usage string) {
+	*p = value
+
+	f.envVar(&countValue{p: p}, flagName, envName, usage)
+}
+
+// countValue implements flag.Value for counters.
+type countValue struct {
+	p *int
+
+	// changed reports whether the flag has been given at least once.
+	// The first occurrence replaces the default value, the next ones add to it.
+	changed bool
+}
+
+func (v *countValue) Set(s string) error {
+	if s == "true" {
+		*v.p = tern(v.changed, *v.p+1, 1)
+		v.changed = true
+
+		return nil
+	}
+
+	parsed, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid count %q: %w", s, err)
+	}
+
+	*v.p = parsed
+	v.changed = true
+
+	return nil
+}
+
+func (v *countValue) String() string {
+	// The flag package calls String on the zero value.
+	if v == nil || v.p == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*v.p)
+}
+
+func (*countValue) Type() string { return "count" }
+
+// IsBoolFlag allows to give the counter flag without a value.
+func (*countValue) IsBoolFlag() bool { return true }
+
+// negatedValue implements flag.Value which sets the boolean value to the opposite one.
+type negatedValue struct {
+	v flag.Value
+}
+
+func (n *negatedValue) Set(s string) error {
+	parsed, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("invalid boolean value %q: %w", s, err)
+	}
+
+	return n.v.Set(strconv.FormatBool(!parsed))
+}
+
+func (n *negatedValue) String() string {
+	// The flag package calls String on the zero value.
+	if n == nil || n.v == nil {
+		return ""
+	}
+
+	parsed, err := strconv.ParseBool(n.v.String())
+	if err != nil {
+		return ""
+	}
+
+	return strconv.FormatBool(!parsed)
+}
+
+func (*negatedValue) Type() string { return "bool" }
+
+// IsBoolFlag allows to give the negating flag without a value.
+func (*negatedValue) IsBoolFlag() bool { return true }
+
+// expandCounters expands the repeated counter flags given as one argument,
+// e.g. -vvv, into separate arguments: -v -v -v. Only the arguments before
+// the first positional argument or the "--" terminator are expanded,
+// following the rules of the flag package.
+func (f *FlagSet) expandCounters(args []string) []string {
+	expanded := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" || len(arg) < 2 || arg[0] != '-' {
+			return append(expanded, args[i:]...)
+		}
+
+		name, _, hasValue := strings.Cut(strings.TrimPrefix(arg[1:], "-"), "=")
+
+		if fl := f.Lookup(name); fl != nil {
+			expanded = append(expanded, arg)
+
+			// The next argument is the value of the flag.
+			if !hasValue && !isBoolFlag(fl.Value) && i+1 < len(args) {
+				i++
+				expanded = append(expanded, args[i])
+			}
+
+			continue
+		}
+
+		short, count := repeatedRune(name)
+		if fl := f.Lookup(short); !hasValue && count > 1 && fl != nil {
+			if _, ok := fl.Value.(*countValue); ok {
+				for range count {
+					expanded = append(expanded, "-"+short)
+				}
+
+				continue
+			}
+		}
+
+		expanded = append(expanded, arg)
+	}
+
+	return expanded
+}
+
+// repeatedRune returns the rune which s consists of as a string
+// together with the number of repetitions. The count is zero
+// when s consists of different runes.
+func repeatedRune(s string) (string, int) {
+	r, _ := utf8.DecodeRuneInString(s)
+
+	for _, c := range s {
+		if c != r {
+			return "", 0
+		}
+	}
+
+	return string(r), utf8.RuneCountInString(s)
+}
+
+// isBoolFlag reports whether the flag value can be given without a value.
+func isBoolFlag(v flag.Value) bool {
+	b, ok := v.(interface{ IsBoolFlag() bool })
+
+	return ok && b.IsBoolFlag()
+}
@@ -0,0 +1,148 @@
+package scotty
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlagSet_CountVarE(t *testing.T) {
+	type tcase struct {
+		want     int
+		env      string
+		args     []string
+		wantArgs []string
+	}
+
+	tests := map[string]tcase{
+		"Default":     {want: 0},
+		"Once":        {want: 1, args: []string{"-v"}},
+		"Repeated":    {want: 3, args: []string{"-v", "-v", "-v"}},
+		"Combined":    {want: 3, args: []string{"-vvv"}},
+		"Double dash": {want: 2, args: []string{"--vv"}},
+		"Mixed":       {want: 4, args: []string{"-vv", "-name", "x", "-v", "-v"}},
+		"Explicit":    {want: 5, args: []string{"-v=5"}},
+		"Env":         {want: 2, env: "2"},
+		"InvalidEnv":  {want: 0, env: "lalala"},
+		"BothSet":     {want: 1, env: "2", args: []string{"-v"}},
+		"Flag value":  {want: 0, args: []string{"-name", "-vvv"}},
+		"Positional":  {want: 1, args: []string{"-v", "run", "-vvv"}, wantArgs: []string{"run", "-vvv"}},
+		"Terminator":  {want: 0, args: []string{"--", "-vvv"}, wantArgs: []string{"-vvv"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TEST_VERBOSITY", tc.env)
+
+			f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+
+			var (
+				got  int
+				name string
+			)
+
+			f.CountVarE(&got, "v", "TEST_VERBOSITY", 0, "")
+			f.StringVar(&name, "name", "", "")
+
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.want != got {
+				t.Errorf("want := %v, got := %v", tc.want, got)
+			}
+
+			if tc.wantArgs != nil && !reflect.DeepEqual(tc.wantArgs, f.Args()) {
+				t.Errorf("want args := %v, got := %v", tc.wantArgs, f.Args())
+			}
+		})
+	}
+}
+
+func TestCommand_CountHelp(t *testing.T) {
+	var (
+		verbosity int
+		name      string
+	)
+
+	cmd := &Command{
+		Name: "app",
+		SetFlags: func(flags *FlagSet) {
+			flags.CountVarE(&verbosity, "v", "TEST_VERBOSITY", 0, "verbosity")
+			flags.StringVar(&name, "name", "", "name")
+		},
+	}
+
+	var b strings.Builder
+
+	if err := cmd.renderHelp(&b, terminal{width: 120}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Flags:\n" +
+		"  -name string  name\n" +
+		"  -v            verbosity (repeatable; env: TEST_VERBOSITY)\n\n"
+
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+	}
+}
+
+func TestFlagSet_MarkNegatable(t *testing.T) {
+	t.Setenv("TEST_CACHE", "false")
+
+	cmd := &Command{Name: "app"}
+	flags := cmd.Flags()
+	flags.SetOutput(discard{})
+
+	var cache bool
+
+	flags.BoolVarE(&cache, "cache", "TEST_CACHE", true, "use cache")
+	flags.MarkNegatable("cache")
+
+	if err := flags.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if cache {
+		t.Error("expected the environment variable to disable the cache")
+	}
+
+	if err := flags.Parse([]string{"-cache", "-no-cache"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cache {
+		t.Error("expected -no-cache to disable the cache")
+	}
+
+	if err := flags.Parse([]string{"-no-cache=false"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !cache {
+		t.Error("expected -no-cache=false to enable the cache")
+	}
+
+	var b strings.Builder
+
+	if err := cmd.renderHelp(&b, terminal{width: 120}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Flags:\n  -[no-]cache bool  use cache (default: true; env: TEST_CACHE)\n\n"
+
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for non-boolean flag")
+		}
+	}()
+
+	flags.String("name", "", "")
+	flags.MarkNegatable("name")
+}
@@ -72,6 +72,8 @@ func loadDotenv(override bool, paths []string) error {
 			if err := os.Setenv(k, v); err != nil {
 				return fmt.Errorf("dotenv: setenv %s: %w", k, err)
 			}
+
+			markDotenvKey(k)
 		}
 	}
 
@@ -0,0 +1,71 @@
+package scotty
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SetEnumIgnoreCase sets whether the enum flags defined after the call
+// match the allowed values case-insensitively. The matched allowed
+// value is stored in its declared case.
+func (f *FlagSet) SetEnumIgnoreCase(ignoreCase bool) {
+	f.enumIgnoreCase = ignoreCase
+}
+
+// EnumVar defines a string flag with specified name, allowed values, default value, and usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+// The value of the flag is validated during parsing and must be one of the allowed values.
+func (f *FlagSet) EnumVar(p *string, name string, allowed []string, value, usage string) {
+	f.enumVarE(newEnumValue(p, allowed, value, f.enumIgnoreCase), name, "", usage)
+}
+
+// EnumVarE defines a string flag and environment variable with specified name, allowed values, default value,
+// and usage string. The argument p points to a string variable in which to store the value of the flag or
+// environment variable. Flag has priority over environment variable. If flag not set the environment variable
+// value is set by Parse. If the value of environment variable is not one of the allowed values the default value
+// will be used.
+func (f *FlagSet) EnumVarE(p *string, flagName, envName string, allowed []string, value, usage string) {
+	f.enumVarE(newEnumValue(p, allowed, value, f.enumIgnoreCase), flagName, envName, usage)
+}
+
+// enumVarE defines the enum flag optionally bound to the environment variable.
+func (f *FlagSet) enumVarE(v *enumValue, flagName, envName, usage string) {
+	f.envVar(v, flagName, envName, usage)
+	f.metaFor(flagName).enum = slices.Clone(v.allowed)
+}
+
+// enumValue implements flag.Value for strings restricted to the allowed values.
+type enumValue struct {
+	p          *string
+	allowed    []string
+	ignoreCase bool
+}
+
+func newEnumValue(p *string, allowed []string, value string, ignoreCase bool) *enumValue {
+	*p = value
+
+	return &enumValue{p: p, allowed: allowed, ignoreCase: ignoreCase}
+}
+
+func (v *enumValue) Set(s string) error {
+	for _, allowed := range v.allowed {
+		if s == allowed || (v.ignoreCase && strings.EqualFold(s, allowed)) {
+			*v.p = allowed
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid value %q: must be one of %s", s, strings.Join(v.allowed, ", "))
+}
+
+func (v *enumValue) String() string {
+	// The flag package calls String on the zero value.
+	if v == nil || v.p == nil {
+		return ""
+	}
+
+	return *v.p
+}
+
+func (*enumValue) Type() string { return "string" }
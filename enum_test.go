@@ -0,0 +1,144 @@
+package scotty
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestFlagSet_EnumVarE(t *testing.T) {
+	type tcase struct {
+		want    string
+		before  func(t *testing.T, f *FlagSet, got *string)
+		args    []string
+		wantErr bool
+	}
+
+	formats := []string{"json", "yaml", "table"}
+
+	tests := map[string]tcase{
+		"Flag": {
+			want: "json",
+			before: func(t *testing.T, f *FlagSet, got *string) {
+				t.Helper()
+				f.EnumVarE(got, "format", "TEST_FORMAT", formats, "table", "")
+			},
+			args: []string{"-format", "json"},
+		},
+
+		"Default": {
+			want: "table",
+			before: func(t *testing.T, f *FlagSet, got *string) {
+				t.Helper()
+				f.EnumVarE(got, "format", "TEST_FORMAT", formats, "table", "")
+			},
+			args: []string{},
+		},
+
+		"Env": {
+			want: "yaml",
+			before: func(t *testing.T, f *FlagSet, got *string) {
+				t.Helper()
+				t.Setenv("TEST_FORMAT", "yaml")
+				f.EnumVarE(got, "format", "TEST_FORMAT", formats, "table", "")
+			},
+			args: []string{},
+		},
+
+		"BothSet": {
+			want: "json",
+			before: func(t *testing.T, f *FlagSet, got *string) {
+				t.Helper()
+				t.Setenv("TEST_FORMAT", "yaml")
+				f.EnumVarE(got, "format", "TEST_FORMAT", formats, "table", "")
+			},
+			args: []string{"-format=json"},
+		},
+
+		"InvalidEnv": {
+			want: "table",
+			before: func(t *testing.T, f *FlagSet, got *string) {
+				t.Helper()
+				t.Setenv("TEST_FORMAT", "xml")
+				f.EnumVarE(got, "format", "TEST_FORMAT", formats, "table", "")
+			},
+			args: []string{},
+		},
+
+		"InvalidFlag": {
+			before: func(t *testing.T, f *FlagSet, got *string) {
+				t.Helper()
+				f.EnumVarE(got, "format", "TEST_FORMAT", formats, "table", "")
+			},
+			args:    []string{"-format", "xml"},
+			wantErr: true,
+		},
+
+		"CaseSensitive": {
+			before: func(t *testing.T, f *FlagSet, got *string) {
+				t.Helper()
+				f.EnumVar(got, "format", formats, "table", "")
+			},
+			args:    []string{"-format", "JSON"},
+			wantErr: true,
+		},
+
+		"IgnoreCase": {
+			want: "json",
+			before: func(t *testing.T, f *FlagSet, got *string) {
+				t.Helper()
+				f.SetEnumIgnoreCase(true)
+				f.EnumVar(got, "format", formats, "table", "")
+			},
+			args: []string{"-format", "JSON"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+			f.SetOutput(discard{})
+
+			var got string
+
+			tc.before(t, f, &got)
+
+			err := f.Parse(tc.args)
+			if tc.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.want != got {
+				t.Errorf("want := %v, got := %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFlagSet_EnumVar_Help(t *testing.T) {
+	var format string
+
+	cmd := &Command{Name: "app"}
+	cmd.Flags().EnumVar(&format, "format", []string{"json", "yaml"}, "json", "output format")
+	cmd.Flags().MarkDeprecated("format", "use -output")
+
+	var b strings.Builder
+
+	if err := cmd.renderHelp(&b, terminal{width: 120}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "  -format string  output format (one of: json, yaml; default: json; deprecated: use -output)\n"
+
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+	}
+}
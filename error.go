@@ -1,6 +1,9 @@
 package scotty
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Error is a custom error type for scotty errors.
 type Error string
@@ -13,6 +16,11 @@ const (
 	ErrInvalidLineEqualSign    Error = "invalid line: missing '='"
 	ErrUnterminatedSingleQuote Error = "unterminated single-quoted value"
 	ErrUnterminatedDoubleQuote Error = "unterminated double-quoted value"
+	ErrMutuallyExclusive       Error = "flags are mutually exclusive"
+	ErrRequiredTogether        Error = "flags are required together"
+	ErrOneRequired             Error = "one of flags is required"
+	ErrInvalidEnvValue         Error = "invalid environment variable value"
+	ErrInvalidField            Error = "invalid field value"
 )
 
 // RequiredFieldError provides details about which required field was not set.
@@ -29,3 +37,131 @@ func (e *RequiredFieldError) Error() string {
 func (*RequiredFieldError) Unwrap() error {
 	return ErrRequiredField
 }
+
+// FlagConstraintError provides details about the violated relationship constraint of flags.
+// It wraps one of ErrMutuallyExclusive, ErrRequiredTogether and ErrOneRequired.
+type FlagConstraintError struct {
+	Err   Error
+	Flags []string
+	Set   []string
+}
+
+func (e *FlagConstraintError) Error() string {
+	return fmt.Sprintf("%s: flags=%s, set=%s", e.Err, strings.Join(e.Flags, ","), strings.Join(e.Set, ","))
+}
+
+func (e *FlagConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// EnvParseError provides details about the environment variable
+// which value can't be parsed to the type of the flag.
+// It wraps both ErrInvalidEnvValue and the parse error.
+type EnvParseError struct {
+	Env   string
+	Flag  string
+	Value string
+	Err   error
+}
+
+func (e *EnvParseError) Error() string {
+	return fmt.Sprintf("%s: env=%s, flag=%s, value=%q: %v", ErrInvalidEnvValue, e.Env, e.Flag, e.Value, e.Err)
+}
+
+func (e *EnvParseError) Unwrap() []error {
+	return []error{ErrInvalidEnvValue, e.Err}
+}
+
+// FieldValidationError provides details about the struct field
+// which value violates the validation rule declared by the struct tag.
+type FieldValidationError struct {
+	FieldName string
+	FlagName  string
+	EnvName   string
+	Rule      string
+	Err       error
+}
+
+func (e *FieldValidationError) Error() string {
+	return fmt.Sprintf("%s: field=%s, flag=%s, env=%s, rule=%s: %v",
+		ErrInvalidField, e.FieldName, e.FlagName, e.EnvName, e.Rule, e.Err,
+	)
+}
+
+func (*FieldValidationError) Unwrap() error {
+	return ErrInvalidField
+}
+
+// ConfigErrors holds all the errors of the configuration found after parsing:
+// EnvParseError, FlagConstraintError, RequiredFieldError and FieldValidationError. Like the errors
+// returned by errors.Join, it matches each of them in errors.Is and errors.As.
+type ConfigErrors []error
+
+// Error returns the multi-line report which describes each error
+// with the names of the flag and the environment variable.
+func (e ConfigErrors) Error() string {
+	var b strings.Builder
+
+	b.WriteString("invalid configuration:")
+
+	for _, err := range e {
+		b.WriteString("\n  - ")
+
+		if r, ok := err.(interface{ report() string }); ok {
+			b.WriteString(r.report())
+			continue
+		}
+
+		b.WriteString(err.Error())
+	}
+
+	return b.String()
+}
+
+func (e ConfigErrors) Unwrap() []error {
+	return e
+}
+
+// report describes the error in the ConfigErrors report.
+func (e *FlagConstraintError) report() string {
+	flags := make([]string, 0, len(e.Flags))
+	for _, name := range e.Flags {
+		flags = append(flags, "-"+name)
+	}
+
+	if len(e.Set) == 0 {
+		return fmt.Sprintf("%s: %s", e.Err, strings.Join(flags, ", "))
+	}
+
+	set := make([]string, 0, len(e.Set))
+	for _, name := range e.Set {
+		set = append(set, "-"+name)
+	}
+
+	return fmt.Sprintf("%s: %s (set: %s)", e.Err, strings.Join(flags, ", "), strings.Join(set, ", "))
+}
+
+// report describes the error in the ConfigErrors report.
+func (e *RequiredFieldError) report() string {
+	return fmt.Sprintf("%s: %s%s", e.FieldName, ErrRequiredField, reportNames(e.FlagName, e.EnvName))
+}
+
+// report describes the error in the ConfigErrors report.
+func (e *EnvParseError) report() string {
+	return fmt.Sprintf("%s %q: %v%s", ErrInvalidEnvValue, e.Value, e.Err, reportNames(e.Flag, e.Env))
+}
+
+// report describes the error in the ConfigErrors report.
+func (e *FieldValidationError) report() string {
+	return fmt.Sprintf("%s: %v%s", e.FieldName, e.Err, reportNames(e.FlagName, e.EnvName))
+}
+
+// reportNames returns the names of the flag and the environment variable
+// to show in the ConfigErrors report, e.g. " (flag: -port, env: PORT)".
+func reportNames(flagName, envName string) string {
+	if envName == "" {
+		return fmt.Sprintf(" (flag: -%s)", flagName)
+	}
+
+	return fmt.Sprintf(" (flag: -%s, env: %s)", flagName, envName)
+}
@@ -97,3 +97,87 @@ func TestRequiredFieldError_As(t *testing.T) {
 		t.Errorf("EnvName = %q, want %q", reqErr.EnvName, "APP_DEBUG")
 	}
 }
+
+func TestFlagConstraintError(t *testing.T) {
+	err := &FlagConstraintError{
+		Err:   ErrMutuallyExclusive,
+		Flags: []string{"file", "url"},
+		Set:   []string{"file", "url"},
+	}
+
+	want := "flags are mutually exclusive: flags=file,url, set=file,url"
+	if got := err.Error(); got != want {
+		t.Errorf("FlagConstraintError.Error() = %q, want %q", got, want)
+	}
+
+	if !errors.Is(err, ErrMutuallyExclusive) {
+		t.Error("errors.Is(FlagConstraintError, ErrMutuallyExclusive) = false, want true")
+	}
+}
+
+func TestEnvParseError(t *testing.T) {
+	parseErr := errors.New("invalid syntax")
+
+	err := &EnvParseError{Env: "PORT", Flag: "port", Value: "80a", Err: parseErr}
+
+	want := `invalid environment variable value: env=PORT, flag=port, value="80a": invalid syntax`
+	if got := err.Error(); got != want {
+		t.Errorf("EnvParseError.Error() = %q, want %q", got, want)
+	}
+
+	if !errors.Is(err, ErrInvalidEnvValue) {
+		t.Error("errors.Is(EnvParseError, ErrInvalidEnvValue) = false, want true")
+	}
+
+	if !errors.Is(err, parseErr) {
+		t.Error("errors.Is(EnvParseError, parseErr) = false, want true")
+	}
+}
+
+func TestFieldValidationError(t *testing.T) {
+	err := &FieldValidationError{
+		FieldName: "Port",
+		FlagName:  "port",
+		EnvName:   "PORT",
+		Rule:      "max: 65535",
+		Err:       errors.New("value must be at most 65535"),
+	}
+
+	want := "invalid field value: field=Port, flag=port, env=PORT, rule=max: 65535: value must be at most 65535"
+	if got := err.Error(); got != want {
+		t.Errorf("FieldValidationError.Error() = %q, want %q", got, want)
+	}
+
+	if !errors.Is(err, ErrInvalidField) {
+		t.Error("errors.Is(FieldValidationError, ErrInvalidField) = false, want true")
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	err := ConfigErrors{
+		&RequiredFieldError{FieldName: "DB.Host", FlagName: "db-host", EnvName: "DB_HOST"},
+		&EnvParseError{Env: "PORT", Flag: "port", Value: "80a", Err: errors.New("parse error")},
+		&FieldValidationError{FieldName: "Workers", FlagName: "workers", Rule: "min: 1", Err: errors.New("value must be at least 1")},
+		errors.New("other error"),
+	}
+
+	want := "invalid configuration:\n" +
+		"  - DB.Host: required field not set (flag: -db-host, env: DB_HOST)\n" +
+		"  - invalid environment variable value \"80a\": parse error (flag: -port, env: PORT)\n" +
+		"  - Workers: value must be at least 1 (flag: -workers)\n" +
+		"  - other error"
+	if got := err.Error(); got != want {
+		t.Errorf("ConfigErrors.Error() = %q, want %q", got, want)
+	}
+
+	for _, target := range []error{ErrRequiredField, ErrInvalidEnvValue, ErrInvalidField} {
+		if !errors.Is(err, target) {
+			t.Errorf("errors.Is(ConfigErrors, %v) = false, want true", target)
+		}
+	}
+
+	var reqErr *RequiredFieldError
+	if !errors.As(err, &reqErr) || reqErr.FieldName != "DB.Host" {
+		t.Errorf("errors.As(ConfigErrors, *RequiredFieldError) = %v", reqErr)
+	}
+}
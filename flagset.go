@@ -1,10 +1,21 @@
 package scotty
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"flag"
+	"fmt"
+	"net/netip"
+	"net/url"
 	"os"
-	"strconv"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
 )
 
 // FlagSet wraps flag.FlagSet and adds a few methods like
@@ -15,17 +26,111 @@ type FlagSet struct {
 	// config holds the bound configuration struct.
 	config any
 
-	// requiredFields tracks fields that must have non-zero values.
+	// requiredFields tracks fields that must be provided.
 	requiredFields []requiredFieldInfo
+
+	// meta holds additional information about flags
+	// which the standard flag.Flag has no room for.
+	meta map[string]*flagMeta
+
+	// groups holds the names of flag groups in the order of their declaration.
+	groups []string
+
+	// sliceSep holds the separator of items for newly defined slice and map flags.
+	sliceSep rune
+
+	// duplicateKeys holds the duplicate key policy for newly defined map flags.
+	duplicateKeys DuplicateKeyPolicy
+
+	// enumIgnoreCase makes newly defined enum flags case-insensitive.
+	enumIgnoreCase bool
+
+	// constraints holds the relationship constraints of flags.
+	constraints []flagConstraint
+
+	// validators holds the nested structs of the config implementing ConfigValidator.
+	validators []nestedValidator
+
+	// validations holds the validation rules of the config fields.
+	validations []fieldValidation
+
+	// strictEnv enables reporting environment variable values which can't be parsed.
+	strictEnv bool
+
+	// autoEnv enables deriving environment variable names from flag names.
+	// It is nil unless set, then the mode of the parent is used.
+	autoEnv *bool
+
+	// envPrefix holds the prefix of the derived environment variable names.
+	// It is nil unless set, then the prefix of the parent is used.
+	envPrefix *string
+
+	// parent holds the flag set of the parent command.
+	parent *FlagSet
+}
+
+// flagMeta holds additional information about a single flag.
+type flagMeta struct {
+	// placeholder overrides the type name shown in the help output.
+	placeholder string
+
+	// group holds the name of the group the flag belongs to in the help output.
+	group string
+
+	// inherited reports whether the flag is a persistent flag
+	// which has been defined by one of the ancestor commands.
+	inherited bool
+
+	// hidden hides the flag from the help output.
+	hidden bool
+
+	// deprecated holds the deprecation message of the flag.
+	deprecated string
+
+	// deprecationWarning makes the deprecation warning to be printed once.
+	deprecationWarning sync.Once
+
+	// env holds the name of the environment variable bound to the flag.
+	env string
+
+	// required reports whether the flag must be provided.
+	required bool
+
+	// optional reports whether the flag is bound to the pointer field
+	// which stays nil unless the flag is provided.
+	optional bool
+
+	// enum holds the allowed values of the flag.
+	enum []string
+
+	// secret reports whether the flag value is masked in the output.
+	secret bool
+
+	// rules holds the descriptions of the validation rules of the flag value.
+	rules []string
+
+	// autoEnv derives the name of the environment variable from the flag name
+	// regardless of the automatic mode of the flag set, see AutoNames.
+	autoEnv bool
+
+	// negation holds the name of the flag which negates the boolean flag.
+	negation string
+
+	// source holds the source of the flag value.
+	source Source
+
+	// setEnv overrides setting the flag value from the environment variable.
+	setEnv func(string) error
 }
 
 // BindConfig binds a config struct to the flagset.
 // The cfg argument must be a pointer to a struct with appropriate tags.
-// Tags supported: flag, env, default, usage, required.
-func (f *FlagSet) BindConfig(cfg any) error {
+// Tags supported: flag, env, default, usage, required, placeholder, group,
+// hidden, deprecated. See BindOption for the options of binding.
+func (f *FlagSet) BindConfig(cfg any, options ...BindOption) error {
 	f.config = cfg
 
-	return bindConfigToFlagSet(f, cfg)
+	return bindConfigToFlagSet(f, cfg, newBindOptions(options))
 }
 
 // Config returns the bound config. Returns nil if no config was bound.
@@ -34,75 +139,520 @@ func (f *FlagSet) Config() any {
 	return f.config
 }
 
+// SetGroup puts the named flags into the group with the given name.
+// Grouped flags are shown in the help output in a separate section
+// titled by the group name. Sections follow the order in which
+// the groups have been declared.
+func (f *FlagSet) SetGroup(group string, names ...string) {
+	if group != "" && !slices.Contains(f.groups, group) {
+		f.groups = append(f.groups, group)
+	}
+
+	for _, name := range names {
+		f.metaFor(name).group = group
+	}
+}
+
+// MarkHidden hides the named flags from the help output.
+// Hidden flags can still be set.
+func (f *FlagSet) MarkHidden(names ...string) {
+	for _, name := range names {
+		f.metaFor(name).hidden = true
+	}
+}
+
+// MarkDeprecated marks the named flag as deprecated. The message should
+// explain what to use instead. Deprecated flag still works, but prints
+// a warning with the message the first time it is set.
+func (f *FlagSet) MarkDeprecated(name, message string) {
+	f.metaFor(name).deprecated = message
+}
+
+// MarkNegatable defines the negating flag for each named boolean flag:
+// -no-cache for -cache. Setting the negating flag sets the boolean flag
+// to the opposite value. The help output shows both flags as -[no-]cache.
+// Panics if the named flag is not defined or is not a boolean flag.
+func (f *FlagSet) MarkNegatable(names ...string) {
+	for _, name := range names {
+		fl := f.Lookup(name)
+		if fl == nil || !isBoolFlag(fl.Value) {
+			panic(fmt.Errorf("flag -%s is not a boolean flag", name))
+		}
+
+		negation := "no-" + name
+
+		f.Var(&negatedValue{v: fl.Value}, negation, "negates -"+name)
+		f.metaFor(name).negation = negation
+		f.metaFor(negation).hidden = true
+	}
+}
+
+// Parse parses flag definitions from the argument list, which should not
+// include the command name. Repeated counter flags given as one argument,
+// e.g. -vvv, are expanded before parsing. See flag.FlagSet.Parse.
+// After parsing, the flags which have not been set take the values
+// of their environment variables, or of the files named by the variables
+// with the _FILE suffix, and the source of each flag value is recorded,
+// see Source. In the strict mode the environment variable
+// values which can't be parsed are reported as EnvParseError.
+// The files which can't be read are reported in any mode.
+// The embedded flag.FlagSet.Parse doesn't apply the environment variables.
+func (f *FlagSet) Parse(arguments []string) error {
+	envErrs, err := f.parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(envErrs...)
+}
+
+// parse parses the arguments and applies the environment variables like Parse,
+// but returns the errors of the environment variable values separately.
+func (f *FlagSet) parse(arguments []string) ([]error, error) {
+	if err := f.FlagSet.Parse(f.expandCounters(arguments)); err != nil {
+		return nil, err
+	}
+
+	f.Visit(func(fl *flag.Flag) {
+		f.metaFor(fl.Name).source = SourceFlag
+
+		// The negation sets the value of the negated flag.
+		if f.isNegation(fl.Name) {
+			f.metaFor(strings.TrimPrefix(fl.Name, "no-")).source = SourceFlag
+		}
+	})
+
+	return f.applyEnv(f.isStrictEnv()), nil
+}
+
+// SetStrictEnv enables or disables the strict mode of parsing environment variables
+// for the flag set. By default, an environment variable value which can't be parsed
+// is ignored and the flag keeps its default value. In the strict mode, Parse returns
+// EnvParseError for each such variable. See SetStrictEnv for enabling it globally.
+func (f *FlagSet) SetStrictEnv(strict bool) { f.strictEnv = strict }
+
+// isStrictEnv reports whether the strict mode of parsing environment
+// variables is enabled for the flag set or globally.
+func (f *FlagSet) isStrictEnv() bool { return f.strictEnv || strictEnv.Load() }
+
+// strictEnv enables the strict mode of parsing environment variables for all flag sets.
+var strictEnv atomic.Bool
+
+// SetStrictEnv enables or disables the strict mode of parsing environment variables
+// for all flag sets. See FlagSet.SetStrictEnv.
+func SetStrictEnv(strict bool) { strictEnv.Store(strict) }
+
+// SetEnvPrefix sets the prefix of the environment variable names derived
+// from the flag names in the automatic mode, e.g. the prefix MYAPP gives
+// MYAPP_LISTEN_ADDR for the -listen-addr flag. See SetAutoEnv.
+func (f *FlagSet) SetEnvPrefix(prefix string) { f.envPrefix = &prefix }
+
+// SetAutoEnv enables or disables the automatic mode, in which every flag
+// without an explicit environment variable is bound to the variable named
+// after the flag: -listen-addr is bound to LISTEN_ADDR, or to MYAPP_LISTEN_ADDR
+// with the MYAPP prefix. Subcommands which don't set the mode and the prefix
+// take them from the parent command when parsing, so persistent flags are bound
+// to the same variables everywhere.
+func (f *FlagSet) SetAutoEnv(auto bool) { f.autoEnv = &auto }
+
+// isAutoEnv reports whether the automatic mode is enabled
+// for the flag set or, unless set, for its parent.
+func (f *FlagSet) isAutoEnv() bool {
+	switch {
+	case f.autoEnv != nil:
+		return *f.autoEnv
+
+	case f.parent != nil:
+		return f.parent.isAutoEnv()
+
+	default:
+		return false
+	}
+}
+
+// autoEnvPrefix returns the prefix of the derived environment variable
+// names set for the flag set or, unless set, for its parent.
+func (f *FlagSet) autoEnvPrefix() string {
+	switch {
+	case f.envPrefix != nil:
+		return *f.envPrefix
+
+	case f.parent != nil:
+		return f.parent.autoEnvPrefix()
+
+	default:
+		return ""
+	}
+}
+
+// envName returns the name of the environment variable bound to the flag:
+// the explicit one, or the one derived from the flag name in the automatic mode.
+func (f *FlagSet) envName(flagName string) string {
+	m := f.lookupMeta(flagName)
+	if m.env != "" || !(m.autoEnv || f.isAutoEnv()) || f.isNegation(flagName) {
+		return m.env
+	}
+
+	return f.derivedEnvName(flagName)
+}
+
+// derivedEnvName returns the environment variable name derived from the flag name
+// and the prefix: upper case letters and digits separated by underscores.
+func (f *FlagSet) derivedEnvName(flagName string) string {
+	name := envCase(flagName)
+
+	prefix := f.autoEnvPrefix()
+	if prefix == "" {
+		return name
+	}
+
+	return strings.TrimSuffix(prefix, "_") + "_" + name
+}
+
+// envCase converts the name to the environment variable case:
+// upper case letters and digits separated by underscores.
+func envCase(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+
+		return '_'
+	}, name)
+}
+
+// isNegation reports whether the flag negates another boolean flag.
+func (f *FlagSet) isNegation(name string) bool {
+	positive, ok := strings.CutPrefix(name, "no-")
+
+	return ok && f.lookupMeta(positive).negation == name
+}
+
+// applyEnv sets the flags which have no value from the command line
+// to the values of their environment variables. The flags keep their
+// default values when the environment variable values can't be parsed,
+// such values are returned as EnvParseError in the strict mode.
+// The files named by the variables which can't be read are always returned.
+func (f *FlagSet) applyEnv(strict bool) []error {
+	var errs []error
+
+	f.VisitAll(func(fl *flag.Flag) {
+		env := f.envName(fl.Name)
+		if env == "" || f.lookupMeta(fl.Name).source != SourceDefault {
+			return
+		}
+
+		m := f.metaFor(fl.Name)
+
+		value, from, err := lookupEnv(env)
+		if err != nil {
+			errs = append(errs, &EnvParseError{Env: from, Flag: fl.Name, Value: os.Getenv(from), Err: err})
+			return
+		}
+
+		if value == "" {
+			return
+		}
+
+		saved := fl.Value.String()
+
+		set := tern(m.setEnv != nil, m.setEnv, fl.Value.Set)
+		if err := set(value); err != nil {
+			// Values of the flag package are set to zero on failure.
+			if fl.Value.String() != saved {
+				//nolint:errcheck // The value has been formatted by the flag value itself.
+				fl.Value.Set(saved)
+			}
+
+			if !strict {
+				return
+			}
+
+			if m.secret {
+				value, err = maskSecret(value), &secretError{err: err}
+			}
+
+			errs = append(errs, &EnvParseError{Env: from, Flag: fl.Name, Value: value, Err: err})
+
+			return
+		}
+
+		m.source = envSource(from)
+	})
+
+	return errs
+}
+
+// envVar defines the flag of the value bound to the environment variable.
+func (f *FlagSet) envVar(v flag.Value, flagName, envName, usage string) {
+	f.Var(v, flagName, usage)
+	f.metaFor(flagName).env = envName
+}
+
+// warnDeprecated prints the warning for each deprecated flag that has been
+// provided in the command line or by the environment variable. The warning
+// for each flag is printed only once.
+func (f *FlagSet) warnDeprecated() {
+	f.VisitAll(func(fl *flag.Flag) {
+		m := f.lookupMeta(fl.Name)
+		if m.deprecated == "" || !f.Changed(fl.Name) {
+			return
+		}
+
+		m.deprecationWarning.Do(func() {
+			if m.source == SourceFlag {
+				fmt.Fprintf(f.Output(), "Flag -%s is deprecated, %s\n", fl.Name, m.deprecated)
+				return
+			}
+
+			fmt.Fprintf(f.Output(), "Flag -%s set by %s is deprecated, %s\n", fl.Name, f.envName(fl.Name), m.deprecated)
+		})
+	})
+}
+
+// isHidden reports whether the named flag is hidden from the help output.
+func (f *FlagSet) isHidden(name string) bool {
+	return f.lookupMeta(name).hidden
+}
+
 // StringVarE defines a string flag and environment variable with specified name, default value, and usage string.
 // The argument p points to a string variable in which to store the value of the flag or environment variable.
-// Flag has priority over environment variable. If flag not set the environment variable value will be used.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
 // If the value of environment variable can't be parsed to destination type the default value will be used.
 func (f *FlagSet) StringVarE(p *string, flagName, envName, value, usage string) {
-	f.StringVar(p, flagName, tern(os.Getenv(envName) != "", os.Getenv(envName), value), usage)
+	f.StringVar(p, flagName, value, usage)
+	f.metaFor(flagName).env = envName
 }
 
 // BoolVarE defines a bool flag and environment variable with specified name, default value, and usage string.
 // The argument p points to a bool variable in which to store the value of the flag or environment variable.
-// Flag has priority over environment variable. If flag not set the environment variable value will be used.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
 // If the value of environment variable can't be parsed to destination type the default value will be used.
 func (f *FlagSet) BoolVarE(p *bool, flagName, envName string, value bool, usage string) {
-	parsed, err := strconv.ParseBool(os.Getenv(envName))
-	f.BoolVar(p, flagName, tern(err == nil, parsed, value), usage)
+	f.BoolVar(p, flagName, value, usage)
+	f.metaFor(flagName).env = envName
 }
 
 // IntVarE defines an int flag and environment variable with specified name, default value, and usage string.
 // The argument p points to an int variable in which to store the value of the flag or environment variable.
-// Flag has priority over environment variable. If flag not set the environment variable value will be used.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
 // If the value of environment variable can't be parsed to destination type the default value will be used.
 func (f *FlagSet) IntVarE(p *int, flagName, envName string, value int, usage string) {
-	parsed, err := strconv.Atoi(os.Getenv(envName))
-	f.IntVar(p, flagName, tern(err == nil, parsed, value), usage)
+	f.IntVar(p, flagName, value, usage)
+	f.metaFor(flagName).env = envName
 }
 
 // Int64VarE defines an int64 flag and environment variable with specified name, default value, and usage string.
 // The argument p points to an int64 variable in which to store the value of the flag or environment variable.
-// Flag has priority over environment variable. If flag not set the environment variable value will be used.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
 // If the value of environment variable can't be parsed to destination type the default value will be used.
 func (f *FlagSet) Int64VarE(p *int64, flagName, envName string, value int64, usage string) {
-	parsed, err := strconv.Atoi(os.Getenv(envName))
-	f.Int64Var(p, flagName, tern(err == nil, int64(parsed), value), usage)
+	f.Int64Var(p, flagName, value, usage)
+	f.metaFor(flagName).env = envName
 }
 
 // Float64VarE defines a float64 flag and environment variable with specified name, default value, and usage string.
 // The argument p points to a float64 variable in which to store the value of the flag or environment variable.
-// Flag has priority over environment variable. If flag not set the environment variable value will be used.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
 // If the value of environment variable can't be parsed to destination type the default value will be used.
 func (f *FlagSet) Float64VarE(p *float64, flagName, envName string, value float64, usage string) {
-	parsed, err := strconv.ParseFloat(os.Getenv(envName), 64)
-	f.Float64Var(p, flagName, tern(err == nil, parsed, value), usage)
+	f.Float64Var(p, flagName, value, usage)
+	f.metaFor(flagName).env = envName
 }
 
 // UintVarE defines an uint flag and environment variable with specified name, default value, and usage string.
 // The argument p points to an uint variable in which to store the value of the flag or environment variable.
-// Flag has priority over environment variable. If flag not set the environment variable value will be used.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
 // If the value of environment variable can't be parsed to destination type the default value will be used.
 func (f *FlagSet) UintVarE(p *uint, flagName, envName string, value uint, usage string) {
-	parsed, err := strconv.ParseUint(os.Getenv(envName), 10, strconv.IntSize)
-	f.UintVar(p, flagName, tern(err == nil, uint(parsed), value), usage)
+	f.UintVar(p, flagName, value, usage)
+	f.metaFor(flagName).env = envName
 }
 
 // Uint64VarE defines an uint64 flag and environment variable with specified name, default value, and usage string.
 // The argument p points to an uint64 variable in which to store the value of the flag or environment variable.
-// Flag has priority over environment variable. If flag not set the environment variable value will be used.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
 // If the value of environment variable can't be parsed to destination type the default value will be used.
 func (f *FlagSet) Uint64VarE(p *uint64, flagName, envName string, value uint64, usage string) {
-	parsed, err := strconv.ParseUint(os.Getenv(envName), 10, 64)
-	f.Uint64Var(p, flagName, tern(err == nil, parsed, value), usage)
+	f.Uint64Var(p, flagName, value, usage)
+	f.metaFor(flagName).env = envName
 }
 
 // DurationVarE defines a time.Duration flag and environment variable with specified name, default value, and usage string.
 // The argument p points to a time.Duration variable in which to store the value of the flag or environment variable.
-// Flag has priority over environment variable. If flag not set the environment variable value will be used.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
 // If the value of environment variable can't be parsed to destination type the default value will be used.
 func (f *FlagSet) DurationVarE(p *time.Duration, flagName, envName string, value time.Duration, usage string) {
-	parsed, err := time.ParseDuration(os.Getenv(envName))
-	f.DurationVar(p, flagName, tern(err == nil, parsed, value), usage)
+	f.DurationVar(p, flagName, value, usage)
+	f.metaFor(flagName).env = envName
+}
+
+// Int8VarE defines an int8 flag and environment variable with specified name, default value, and usage string.
+// The argument p points to an int8 variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+// Values which overflow the type are parsing errors.
+func (f *FlagSet) Int8VarE(p *int8, flagName, envName string, value int8, usage string) {
+	varE(f, newGenericValue(p, "int8", parseSigned[int8], formatSigned[int8]), flagName, envName, value, usage)
+}
+
+// Int16VarE defines an int16 flag and environment variable with specified name, default value, and usage string.
+// The argument p points to an int16 variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+// Values which overflow the type are parsing errors.
+func (f *FlagSet) Int16VarE(p *int16, flagName, envName string, value int16, usage string) {
+	varE(f, newGenericValue(p, "int16", parseSigned[int16], formatSigned[int16]), flagName, envName, value, usage)
+}
+
+// Int32VarE defines an int32 flag and environment variable with specified name, default value, and usage string.
+// The argument p points to an int32 variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+// Values which overflow the type are parsing errors.
+func (f *FlagSet) Int32VarE(p *int32, flagName, envName string, value int32, usage string) {
+	varE(f, newGenericValue(p, "int32", parseSigned[int32], formatSigned[int32]), flagName, envName, value, usage)
+}
+
+// Uint8VarE defines an uint8 flag and environment variable with specified name, default value, and usage string.
+// The argument p points to an uint8 variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+// Values which overflow the type are parsing errors.
+func (f *FlagSet) Uint8VarE(p *uint8, flagName, envName string, value uint8, usage string) {
+	varE(f, newGenericValue(p, "uint8", parseUnsigned[uint8], formatUnsigned[uint8]), flagName, envName, value, usage)
+}
+
+// Uint16VarE defines an uint16 flag and environment variable with specified name, default value, and usage string.
+// The argument p points to an uint16 variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+// Values which overflow the type are parsing errors.
+func (f *FlagSet) Uint16VarE(p *uint16, flagName, envName string, value uint16, usage string) {
+	varE(f, newGenericValue(p, "uint16", parseUnsigned[uint16], formatUnsigned[uint16]), flagName, envName, value, usage)
+}
+
+// Uint32VarE defines an uint32 flag and environment variable with specified name, default value, and usage string.
+// The argument p points to an uint32 variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+// Values which overflow the type are parsing errors.
+func (f *FlagSet) Uint32VarE(p *uint32, flagName, envName string, value uint32, usage string) {
+	varE(f, newGenericValue(p, "uint32", parseUnsigned[uint32], formatUnsigned[uint32]), flagName, envName, value, usage)
+}
+
+// Float32VarE defines a float32 flag and environment variable with specified name, default value, and usage string.
+// The argument p points to a float32 variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+// Values which overflow the type are parsing errors.
+func (f *FlagSet) Float32VarE(p *float32, flagName, envName string, value float32, usage string) {
+	varE(f, newGenericValue(p, "float32", parseFloat32, formatFloat32), flagName, envName, value, usage)
+}
+
+// TimeVarE defines a time.Time flag and environment variable with specified name, layout, default value,
+// and usage string. The argument p points to a time.Time variable in which to store the value of the flag
+// or environment variable. The values are parsed and formatted with the layout, time.RFC3339 is used when
+// the layout is empty. Flag has priority over environment variable. If flag not set the environment variable
+// value is set by Parse. If the value of environment variable can't be parsed to destination type the default
+// value will be used.
+func (f *FlagSet) TimeVarE(p *time.Time, flagName, envName, layout string, value time.Time, usage string) {
+	layout = tern(layout != "", layout, time.RFC3339)
+	varE(f, newGenericValue(p, "time", parseTime(layout), formatTime(layout)), flagName, envName, value, usage)
+}
+
+// URLVarE defines a *url.URL flag and environment variable with specified name, default value, and usage string.
+// The argument p points to a *url.URL variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+func (f *FlagSet) URLVarE(p **url.URL, flagName, envName string, value *url.URL, usage string) {
+	varE(f, newGenericValue(p, "url", url.Parse, formatURL), flagName, envName, value, usage)
+}
+
+// AddrVarE defines a netip.Addr flag and environment variable with specified name, default value, and usage string.
+// The argument p points to a netip.Addr variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+func (f *FlagSet) AddrVarE(p *netip.Addr, flagName, envName string, value netip.Addr, usage string) {
+	varE(f, newGenericValue(p, "addr", netip.ParseAddr, formatAddr), flagName, envName, value, usage)
+}
+
+// AddrPortVarE defines a netip.AddrPort flag and environment variable with specified name, default value,
+// and usage string. The argument p points to a netip.AddrPort variable in which to store the value of the flag
+// or environment variable. Flag has priority over environment variable. If flag not set the environment variable
+// value is set by Parse. If the value of environment variable can't be parsed to destination type the default value
+// will be used.
+func (f *FlagSet) AddrPortVarE(p *netip.AddrPort, flagName, envName string, value netip.AddrPort, usage string) {
+	varE(f, newGenericValue(p, "addrport", netip.ParseAddrPort, formatAddrPort), flagName, envName, value, usage)
+}
+
+// PrefixVarE defines a netip.Prefix flag and environment variable with specified name, default value, and usage string.
+// The argument p points to a netip.Prefix variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+func (f *FlagSet) PrefixVarE(p *netip.Prefix, flagName, envName string, value netip.Prefix, usage string) {
+	varE(f, newGenericValue(p, "prefix", netip.ParsePrefix, formatPrefix), flagName, envName, value, usage)
+}
+
+// RegexpVarE defines a *regexp.Regexp flag and environment variable with specified name, default value,
+// and usage string. The argument p points to a *regexp.Regexp variable in which to store the compiled value
+// of the flag or environment variable. Flag has priority over environment variable. If flag not set the
+// environment variable value is set by Parse. If the value of environment variable can't be compiled the
+// default value will be used.
+func (f *FlagSet) RegexpVarE(p **regexp.Regexp, flagName, envName string, value *regexp.Regexp, usage string) {
+	varE(f, newGenericValue(p, "regexp", regexp.Compile, formatRegexp), flagName, envName, value, usage)
+}
+
+// FileModeVarE defines an os.FileMode flag and environment variable with specified name, default value,
+// and usage string. The argument p points to an os.FileMode variable in which to store the value of the flag
+// or environment variable. The values are octal permission bits, e.g. 0644. Flag has priority over environment
+// variable. If flag not set the environment variable value is set by Parse. If the value of environment variable
+// can't be parsed to destination type the default value will be used.
+func (f *FlagSet) FileModeVarE(p *os.FileMode, flagName, envName string, value os.FileMode, usage string) {
+	varE(f, newGenericValue(p, "filemode", parseFileMode, formatFileMode), flagName, envName, value, usage)
+}
+
+// BytesBase64VarE defines a []byte flag and environment variable with specified name, default value,
+// and usage string. The argument p points to a []byte variable in which to store the decoded value of
+// the flag or environment variable. The values are encoded with the standard base64 encoding. Flag has
+// priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be decoded the default value will be used.
+func (f *FlagSet) BytesBase64VarE(p *[]byte, flagName, envName string, value []byte, usage string) {
+	varE(f, newGenericValue(p, "base64", base64.StdEncoding.DecodeString, base64.StdEncoding.EncodeToString), flagName, envName, value, usage)
+}
+
+// BytesHexVarE defines a []byte flag and environment variable with specified name, default value,
+// and usage string. The argument p points to a []byte variable in which to store the decoded value of
+// the flag or environment variable. The values are hex encoded. Flag has priority over environment
+// variable. If flag not set the environment variable value is set by Parse. If the value of environment
+// variable can't be decoded the default value will be used.
+func (f *FlagSet) BytesHexVarE(p *[]byte, flagName, envName string, value []byte, usage string) {
+	varE(f, newGenericValue(p, "hex", hex.DecodeString, hex.EncodeToString), flagName, envName, value, usage)
+}
+
+// metaFor returns the metadata of the named flag, creating it if needed.
+func (f *FlagSet) metaFor(name string) *flagMeta {
+	if f.meta == nil {
+		f.meta = make(map[string]*flagMeta)
+	}
+
+	m, ok := f.meta[name]
+	if !ok {
+		m = &flagMeta{}
+		f.meta[name] = m
+	}
+
+	return m
+}
+
+// lookupMeta returns the metadata of the named flag. When there is no
+// metadata for the flag, the empty one is returned without storing it.
+func (f *FlagSet) lookupMeta(name string) *flagMeta {
+	if m, ok := f.meta[name]; ok {
+		return m
+	}
+
+	return &flagMeta{}
 }
 
 func tern[T any](cond bool, t, f T) T {
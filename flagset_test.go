@@ -1,8 +1,11 @@
 package scotty
 
 import (
+	"errors"
 	"flag"
+	"math"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -613,6 +616,101 @@ func TestFlagSet_UintVarE(t *testing.T) {
 	}
 }
 
+func TestFlagSet_Int8VarE(t *testing.T) {
+	t.Setenv("TEST_E1", "200")
+
+	f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+	f.SetOutput(discard{})
+
+	var got int8
+
+	f.Int8VarE(&got, "f1", "TEST_E1", 10, "")
+
+	if got != 10 {
+		t.Errorf("want := %v, got := %v", 10, got)
+	}
+
+	if err := f.Parse([]string{"-f1=-128"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got != -128 {
+		t.Errorf("want := %v, got := %v", -128, got)
+	}
+
+	if err := f.Parse([]string{"-f1=128"}); err == nil {
+		t.Error("expected overflow error, got nil")
+	}
+}
+
+func TestFlagSet_Uint16VarE(t *testing.T) {
+	t.Setenv("TEST_E1", "65535")
+
+	f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+	f.SetOutput(discard{})
+
+	var got uint16
+
+	f.Uint16VarE(&got, "f1", "TEST_E1", 10, "")
+
+	if err := f.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if got != 65535 {
+		t.Errorf("want := %v, got := %v", 65535, got)
+	}
+
+	for _, arg := range []string{"-f1=65536", "-f1=-1"} {
+		if err := f.Parse([]string{arg}); err == nil {
+			t.Errorf("expected error for %s, got nil", arg)
+		}
+	}
+}
+
+func TestFlagSet_Float32VarE(t *testing.T) {
+	f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+	f.SetOutput(discard{})
+
+	var got float32
+
+	f.Float32VarE(&got, "f1", "TEST_E1", 1.5, "")
+
+	if err := f.Parse([]string{"-f1=2.25"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got != 2.25 {
+		t.Errorf("want := %v, got := %v", 2.25, got)
+	}
+
+	if err := f.Parse([]string{"-f1=1e39"}); err == nil {
+		t.Error("expected overflow error, got nil")
+	}
+}
+
+func TestFlagSet_TimeVarE(t *testing.T) {
+	t.Setenv("TEST_E1", "2024-02-03")
+
+	f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+
+	var got time.Time
+
+	f.TimeVarE(&got, "f1", "TEST_E1", time.DateOnly, time.Time{}, "")
+
+	if err := f.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("want := %v, got := %v", want, got)
+	}
+
+	if def := f.Lookup("f1").DefValue; def != "" {
+		t.Errorf("want := %q, got := %q", "", def)
+	}
+}
+
 func Test_tern(t *testing.T) {
 	type tcase[T any] struct {
 		cond       bool
@@ -644,3 +742,181 @@ func Test_tern(t *testing.T) {
 		})
 	}
 }
+
+func TestFlagSet_SetStrictEnv(t *testing.T) {
+	t.Setenv("TEST_PORT", "80a")
+	t.Setenv("TEST_TIMEOUT", "5x")
+	t.Setenv("TEST_HOST", "example.com")
+
+	newFlagSet := func(port *int, timeout *time.Duration, host *string) *FlagSet {
+		f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+		f.IntVarE(port, "port", "TEST_PORT", 8080, "")
+		f.DurationVarE(timeout, "timeout", "TEST_TIMEOUT", time.Second, "")
+		f.StringVarE(host, "host", "TEST_HOST", "localhost", "")
+
+		return f
+	}
+
+	t.Run("Lenient", func(t *testing.T) {
+		var (
+			port    int
+			timeout time.Duration
+			host    string
+		)
+
+		if err := newFlagSet(&port, &timeout, &host).Parse(nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if port != 8080 || timeout != time.Second || host != "example.com" {
+			t.Errorf("unexpected values: port=%v, timeout=%v, host=%v", port, timeout, host)
+		}
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		var (
+			port    int
+			timeout time.Duration
+			host    string
+		)
+
+		f := newFlagSet(&port, &timeout, &host)
+		f.SetStrictEnv(true)
+
+		err := f.Parse(nil)
+		if !errors.Is(err, ErrInvalidEnvValue) {
+			t.Fatalf("expected ErrInvalidEnvValue, got %v", err)
+		}
+
+		var envErr *EnvParseError
+		if !errors.As(err, &envErr) {
+			t.Fatalf("expected EnvParseError, got %T", err)
+		}
+
+		if envErr.Env != "TEST_PORT" || envErr.Flag != "port" || envErr.Value != "80a" {
+			t.Errorf("unexpected error details: %+v", envErr)
+		}
+
+		if !strings.Contains(err.Error(), "TEST_TIMEOUT") {
+			t.Errorf("expected all the invalid variables to be reported, got %q", err)
+		}
+
+		if port != 8080 || host != "example.com" {
+			t.Errorf("unexpected values: port=%v, host=%v", port, host)
+		}
+	})
+
+	t.Run("FlagOverridesInvalidEnv", func(t *testing.T) {
+		var (
+			port    int
+			timeout time.Duration
+			host    string
+		)
+
+		f := newFlagSet(&port, &timeout, &host)
+		f.SetStrictEnv(true)
+
+		if err := f.Parse([]string{"-port=80", "-timeout=5s"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Global", func(t *testing.T) {
+		SetStrictEnv(true)
+		t.Cleanup(func() { SetStrictEnv(false) })
+
+		var (
+			port    int
+			timeout time.Duration
+			host    string
+		)
+
+		if err := newFlagSet(&port, &timeout, &host).Parse(nil); !errors.Is(err, ErrInvalidEnvValue) {
+			t.Fatalf("expected ErrInvalidEnvValue, got %v", err)
+		}
+	})
+}
+
+func TestFlagSet_Int64VarE_Env(t *testing.T) {
+	t.Setenv("TEST_E1", "9223372036854775807")
+
+	f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+	f.SetStrictEnv(true)
+
+	var got int64
+
+	f.Int64VarE(&got, "f1", "TEST_E1", 0, "")
+
+	if err := f.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if got != math.MaxInt64 {
+		t.Errorf("want := %v, got := %v", int64(math.MaxInt64), got)
+	}
+}
+
+func TestFlagSet_SetAutoEnv(t *testing.T) {
+	t.Setenv("MYAPP_LISTEN_ADDR", ":9090")
+	t.Setenv("MYAPP_DEBUG", "true")
+	t.Setenv("MYAPP_NO_CACHE", "true")
+	t.Setenv("MYAPP_TIMEOUT", "1m")
+	t.Setenv("TEST_TIMEOUT", "5s")
+
+	f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+	f.SetEnvPrefix("MYAPP")
+	f.SetAutoEnv(true)
+
+	var (
+		addr    string
+		debug   bool
+		cache   bool
+		timeout time.Duration
+	)
+
+	f.StringVar(&addr, "listen-addr", ":8080", "")
+	f.BoolVar(&debug, "debug", false, "")
+	f.BoolVar(&cache, "cache", true, "")
+	f.MarkNegatable("cache")
+	f.DurationVarE(&timeout, "timeout", "TEST_TIMEOUT", time.Second, "")
+
+	if err := f.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if addr != ":9090" {
+		t.Errorf("want := %v, got := %v", ":9090", addr)
+	}
+
+	if !debug {
+		t.Errorf("want := %v, got := %v", true, debug)
+	}
+
+	if !cache {
+		t.Error("expected the negating flag not to be bound to the environment variable")
+	}
+
+	if timeout != 5*time.Second {
+		t.Errorf("want := %v, got := %v", 5*time.Second, timeout)
+	}
+
+	tests := map[string]string{
+		"listen-addr": "MYAPP_LISTEN_ADDR",
+		"debug":       "MYAPP_DEBUG",
+		"cache":       "MYAPP_CACHE",
+		"no-cache":    "",
+		"timeout":     "TEST_TIMEOUT",
+	}
+
+	for name, want := range tests {
+		if got := f.envName(name); got != want {
+			t.Errorf("%s: want := %q, got := %q", name, want, got)
+		}
+	}
+
+	f.SetEnvPrefix("")
+
+	if got := f.envName("listen-addr"); got != "LISTEN_ADDR" {
+		t.Errorf("want := %q, got := %q", "LISTEN_ADDR", got)
+	}
+}
@@ -0,0 +1,178 @@
+package scotty
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DuplicateKeyPolicy defines how map flags handle keys given more than once.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeyLast keeps the last value of the duplicate key.
+	DuplicateKeyLast DuplicateKeyPolicy = iota
+
+	// DuplicateKeyFirst keeps the first value of the duplicate key.
+	DuplicateKeyFirst
+
+	// DuplicateKeyError makes the duplicate key a parsing error.
+	DuplicateKeyError
+)
+
+// SetDuplicateKeyPolicy sets how the map flags defined after the call handle
+// keys given more than once. The default policy is DuplicateKeyLast.
+func (f *FlagSet) SetDuplicateKeyPolicy(policy DuplicateKeyPolicy) {
+	f.duplicateKeys = policy
+}
+
+// StringMapVar defines a map[string]string flag with specified name, default value, and usage string.
+// The argument p points to a map[string]string variable in which to store the value of the flag.
+// The flag takes key=value pairs, it can be repeated and each value can hold several separated
+// pairs: -label env=prod -label team=core,tier=1. The first occurrence replaces the default value.
+func (f *FlagSet) StringMapVar(p *map[string]string, name string, value map[string]string, usage string) {
+	v := newMapValue(p, value, f.sliceSeparator(), f.duplicateKeys, "string", parseString, formatString)
+	f.Var(v, name, usage)
+}
+
+// StringMapVarE defines a map[string]string flag and environment variable with specified name, default value,
+// and usage string. The argument p points to a map[string]string variable in which to store the value of the flag
+// or environment variable. Flag has priority over environment variable. If flag not set the environment variable
+// value is set by Parse. The environment variable value is split into pairs the same way as the flag value.
+func (f *FlagSet) StringMapVarE(p *map[string]string, flagName, envName string, value map[string]string, usage string) {
+	v := newMapValue(p, value, f.sliceSeparator(), f.duplicateKeys, "string", parseString, formatString)
+	f.envVar(v, flagName, envName, usage)
+}
+
+// IntMapVar defines a map[string]int flag with specified name, default value, and usage string.
+// The argument p points to a map[string]int variable in which to store the value of the flag.
+// The flag takes key=value pairs, it can be repeated and each value can hold several separated
+// pairs: -limit cpu=2 -limit mem=512,disk=10. The first occurrence replaces the default value.
+func (f *FlagSet) IntMapVar(p *map[string]int, name string, value map[string]int, usage string) {
+	v := newMapValue(p, value, f.sliceSeparator(), f.duplicateKeys, "int", strconv.Atoi, strconv.Itoa)
+	f.Var(v, name, usage)
+}
+
+// IntMapVarE defines a map[string]int flag and environment variable with specified name, default value,
+// and usage string. The argument p points to a map[string]int variable in which to store the value of the flag
+// or environment variable. Flag has priority over environment variable. If flag not set the environment variable
+// value is set by Parse. If the value of environment variable can't be parsed to destination type the default value
+// will be used.
+func (f *FlagSet) IntMapVarE(p *map[string]int, flagName, envName string, value map[string]int, usage string) {
+	v := newMapValue(p, value, f.sliceSeparator(), f.duplicateKeys, "int", strconv.Atoi, strconv.Itoa)
+	f.envVar(v, flagName, envName, usage)
+}
+
+// mapValue implements flag.Value for maps with string keys and values of any type.
+type mapValue[T any] struct {
+	p          *map[string]T
+	sep        rune
+	duplicates DuplicateKeyPolicy
+	typeName   string
+	parse      func(string) (T, error)
+	format     func(T) string
+
+	// changed reports whether the value has been set at least once.
+	// The first Set replaces the default value, the next ones add to it.
+	changed bool
+}
+
+func newMapValue[T any](
+	p *map[string]T,
+	value map[string]T,
+	sep rune,
+	duplicates DuplicateKeyPolicy,
+	typeName string,
+	parse func(string) (T, error),
+	format func(T) string,
+) *mapValue[T] {
+	*p = maps.Clone(value)
+
+	return &mapValue[T]{
+		p:          p,
+		sep:        sep,
+		duplicates: duplicates,
+		typeName:   typeName,
+		parse:      parse,
+		format:     format,
+	}
+}
+
+func (v *mapValue[T]) Set(s string) error {
+	items, err := splitSlice(s, v.sep)
+	if err != nil {
+		return err
+	}
+
+	parsed := make(map[string]T, len(items))
+
+	if v.changed {
+		maps.Copy(parsed, *v.p)
+	}
+
+	for _, item := range items {
+		key, raw, ok := strings.Cut(item, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid pair %q: want key=value", item)
+		}
+
+		value, err := v.parse(raw)
+		if err != nil {
+			return fmt.Errorf("invalid value of key %q: %w", key, err)
+		}
+
+		if _, exists := parsed[key]; exists {
+			switch v.duplicates {
+			case DuplicateKeyFirst:
+				continue
+
+			case DuplicateKeyError:
+				return fmt.Errorf("duplicate key %q", key)
+
+			default:
+			}
+		}
+
+		parsed[key] = value
+	}
+
+	*v.p = parsed
+	v.changed = true
+
+	return nil
+}
+
+func (v *mapValue[T]) String() string {
+	// The flag package calls String on the zero value.
+	if v == nil || v.p == nil {
+		return ""
+	}
+
+	items := make([]string, 0, len(*v.p))
+	for _, key := range slices.Sorted(maps.Keys(*v.p)) {
+		items = append(items, key+"="+v.format((*v.p)[key]))
+	}
+
+	return joinSlice(items, v.sep)
+}
+
+func (v *mapValue[T]) Type() string { return "map[string]" + v.typeName }
+
+// parseDuplicateKeyPolicy parses the duplicate key policy by its name.
+func parseDuplicateKeyPolicy(s string) (DuplicateKeyPolicy, error) {
+	switch s {
+	case "last":
+		return DuplicateKeyLast, nil
+
+	case "first":
+		return DuplicateKeyFirst, nil
+
+	case "error":
+		return DuplicateKeyError, nil
+
+	default:
+		return 0, fmt.Errorf("invalid duplicate key policy: %q", s)
+	}
+}
@@ -0,0 +1,204 @@
+package scotty
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlagSet_StringMapVarE(t *testing.T) {
+	type tcase struct {
+		want    map[string]string
+		before  func(t *testing.T, f *FlagSet, got *map[string]string)
+		args    []string
+		wantErr bool
+	}
+
+	tests := map[string]tcase{
+		"Repeated": {
+			want: map[string]string{"env": "prod", "team": "core", "tier": "1"},
+			before: func(t *testing.T, f *FlagSet, got *map[string]string) {
+				t.Helper()
+				f.StringMapVarE(got, "label", "TEST_LABELS", map[string]string{"def": "1"}, "")
+			},
+			args: []string{"-label", "env=prod", "-label", "team=core,tier=1"},
+		},
+
+		"Quoted": {
+			want: map[string]string{"hosts": "a,b", "expr": "x=y"},
+			before: func(t *testing.T, f *FlagSet, got *map[string]string) {
+				t.Helper()
+				f.StringMapVarE(got, "label", "TEST_LABELS", nil, "")
+			},
+			args: []string{"-label", `"hosts=a,b",expr=x=y`},
+		},
+
+		"Env": {
+			want: map[string]string{"env": "prod", "team": "core"},
+			before: func(t *testing.T, f *FlagSet, got *map[string]string) {
+				t.Helper()
+				t.Setenv("TEST_LABELS", "env=prod,team=core")
+				f.StringMapVarE(got, "label", "TEST_LABELS", map[string]string{"def": "1"}, "")
+			},
+			args: []string{},
+		},
+
+		"BothSet": {
+			want: map[string]string{"env": "dev"},
+			before: func(t *testing.T, f *FlagSet, got *map[string]string) {
+				t.Helper()
+				t.Setenv("TEST_LABELS", "env=prod,team=core")
+				f.StringMapVarE(got, "label", "TEST_LABELS", nil, "")
+			},
+			args: []string{"-label=env=dev"},
+		},
+
+		"InvalidEnv": {
+			want: map[string]string{"def": "1"},
+			before: func(t *testing.T, f *FlagSet, got *map[string]string) {
+				t.Helper()
+				t.Setenv("TEST_LABELS", "lalala")
+				f.StringMapVarE(got, "label", "TEST_LABELS", map[string]string{"def": "1"}, "")
+			},
+			args: []string{},
+		},
+
+		"Duplicate last": {
+			want: map[string]string{"env": "dev"},
+			before: func(t *testing.T, f *FlagSet, got *map[string]string) {
+				t.Helper()
+				f.StringMapVarE(got, "label", "TEST_LABELS", nil, "")
+			},
+			args: []string{"-label", "env=prod", "-label", "env=dev"},
+		},
+
+		"Duplicate first": {
+			want: map[string]string{"env": "prod"},
+			before: func(t *testing.T, f *FlagSet, got *map[string]string) {
+				t.Helper()
+				f.SetDuplicateKeyPolicy(DuplicateKeyFirst)
+				f.StringMapVarE(got, "label", "TEST_LABELS", nil, "")
+			},
+			args: []string{"-label", "env=prod,env=dev"},
+		},
+
+		"Duplicate error": {
+			before: func(t *testing.T, f *FlagSet, got *map[string]string) {
+				t.Helper()
+				f.SetDuplicateKeyPolicy(DuplicateKeyError)
+				f.StringMapVarE(got, "label", "TEST_LABELS", nil, "")
+			},
+			args:    []string{"-label", "env=prod", "-label", "env=dev"},
+			wantErr: true,
+		},
+
+		"Missing equal sign": {
+			before: func(t *testing.T, f *FlagSet, got *map[string]string) {
+				t.Helper()
+				f.StringMapVarE(got, "label", "TEST_LABELS", nil, "")
+			},
+			args:    []string{"-label", "env"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+			f.SetOutput(discard{})
+
+			var got map[string]string
+
+			tc.before(t, f, &got)
+
+			err := f.Parse(tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("wantErr := %v, got := %v", tc.wantErr, err)
+			}
+
+			if !tc.wantErr && !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("want := %v, got := %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFlagSet_IntMapVar(t *testing.T) {
+	f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+	f.SetOutput(discard{})
+
+	var got map[string]int
+
+	f.IntMapVar(&got, "limit", map[string]int{"mem": 512, "cpu": 2}, "")
+
+	fl := f.Lookup("limit")
+
+	if fl.DefValue != "cpu=2,mem=512" {
+		t.Errorf("default := %q, want %q", fl.DefValue, "cpu=2,mem=512")
+	}
+
+	if typ := flagValueType(fl); typ != "map[string]int" {
+		t.Errorf("type := %q, want %q", typ, "map[string]int")
+	}
+
+	if err := f.Parse([]string{"-limit", "cpu=x"}); err == nil {
+		t.Error("expected error for invalid value, got nil")
+	}
+}
+
+func TestCommand_PersistentMapFlag(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want map[string]string
+	}{
+		"Default":    {args: []string{"sub"}, want: map[string]string{"env": "dev"}},
+		"Subcommand": {args: []string{"sub", "-label", "env=prod"}, want: map[string]string{"env": "prod"}},
+		"Parent":     {args: []string{"-label", "env=prod", "sub"}, want: map[string]string{"env": "prod"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			helperDisableStdout(t)
+
+			var labels, got map[string]string
+
+			root := &Command{
+				Name: "app",
+				SetPersistentFlags: func(flags *FlagSet) {
+					flags.SetDuplicateKeyPolicy(DuplicateKeyError)
+					flags.StringMapVar(&labels, "label", map[string]string{"env": "dev"}, "")
+				},
+			}
+
+			root.AddSubcommands(&Command{
+				Name: "sub",
+				Run: func(cmd *Command, args []string) error {
+					got = labels
+					return nil
+				},
+			})
+
+			if err := root.execCommand(tc.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("want := %v, got := %v", tc.want, got)
+			}
+		})
+	}
+
+	cmd := &Command{Name: "app"}
+	cmd.Flags().IntMapVar(new(map[string]int), "limit", map[string]int{"mem": 512, "cpu": 2}, "limits")
+
+	var b strings.Builder
+
+	if err := cmd.renderHelp(&b, terminal{width: 120}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "limits (default: cpu=2,mem=512)"; !strings.Contains(b.String(), want) {
+		t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+	}
+}
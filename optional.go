@@ -0,0 +1,83 @@
+package scotty
+
+import (
+	"flag"
+	"reflect"
+)
+
+// bindOptionalField binds the pointer field to the flag which allocates the value
+// when the flag or its environment variable is provided, so the field stays nil
+// otherwise. The pointed value is bound the same way as a field of its type.
+// The default value, if any, allocates the value right away.
+func bindOptionalField(f *FlagSet, fieldVal reflect.Value, opts fieldOpts) error {
+	elem := reflect.New(fieldVal.Type().Elem())
+
+	// The pointed value is bound to the flag of a scratch flag set
+	// to reuse the binders, the environment variable is bound by f.
+	scratch := &FlagSet{FlagSet: flag.NewFlagSet(opts.flagName, flag.ContinueOnError)}
+
+	elemOpts := opts
+	elemOpts.envName = ""
+
+	if err := bindField(scratch, elem.Elem(), elemOpts); err != nil {
+		return err
+	}
+
+	m := scratch.lookupMeta(opts.flagName)
+
+	v := &optionalValue{
+		field: fieldVal,
+		elem:  elem,
+		inner: scratch.Lookup(opts.flagName).Value,
+		set:   m.setEnv,
+	}
+
+	if opts.defaultVal != "" {
+		fieldVal.Set(elem)
+	}
+
+	f.envVar(v, opts.flagName, opts.envName, opts.usage)
+
+	meta := f.metaFor(opts.flagName)
+	meta.optional = opts.defaultVal == ""
+	meta.enum = m.enum
+	meta.secret = m.secret
+
+	return nil
+}
+
+// optionalValue implements flag.Value for pointer fields. The value is set
+// by the flag value of the pointed type, then the field points to it.
+type optionalValue struct {
+	field reflect.Value
+	elem  reflect.Value
+	inner flag.Value
+
+	// set overrides setting the value by the inner flag value.
+	set func(string) error
+}
+
+func (v *optionalValue) Set(s string) error {
+	set := tern(v.set != nil, v.set, v.inner.Set)
+	if err := set(s); err != nil {
+		return err
+	}
+
+	v.field.Set(v.elem)
+
+	return nil
+}
+
+func (v *optionalValue) String() string {
+	// The flag package calls String on the zero value.
+	if v == nil || v.inner == nil || v.field.IsNil() {
+		return ""
+	}
+
+	return v.inner.String()
+}
+
+func (v *optionalValue) Type() string { return flagValueType(&flag.Flag{Value: v.inner}) }
+
+// IsBoolFlag allows to give the optional boolean flag without a value.
+func (v *optionalValue) IsBoolFlag() bool { return isBoolFlag(v.inner) }
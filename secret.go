@@ -0,0 +1,159 @@
+package scotty
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// secretMask replaces the values of secret flags in the output.
+const secretMask = "******"
+
+// Secret is a string which is masked when formatted by the fmt package
+// or marshaled as text or JSON, so the values of secret config fields
+// don't appear in logs and config dumps. Use Value to get the string.
+// Fields of the type Secret are bound as secret flags, see FlagSet.MarkSecret.
+type Secret string
+
+// Value returns the secret string.
+func (s Secret) Value() string { return string(s) }
+
+// String returns the mask instead of the secret string.
+func (s Secret) String() string { return maskSecret(string(s)) }
+
+// GoString returns the mask instead of the secret string.
+func (s Secret) GoString() string { return fmt.Sprintf("%q", s.String()) }
+
+// Format writes the mask instead of the secret string for all verbs.
+func (s Secret) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, s.GoString())
+		return
+	}
+
+	fmt.Fprint(f, s.String())
+}
+
+// MarshalText implements encoding.TextMarshaler writing the mask instead of the secret string.
+func (s Secret) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// SecretVar defines a secret flag with specified name, default value, and usage string.
+// The argument p points to a Secret variable in which to store the value of the flag.
+func (f *FlagSet) SecretVar(p *Secret, name string, value Secret, usage string) {
+	f.SecretVarE(p, name, "", value, usage)
+}
+
+// SecretVarE defines a secret flag and environment variable with specified name, default value, and usage string.
+// The argument p points to a Secret variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+func (f *FlagSet) SecretVarE(p *Secret, flagName, envName string, value Secret, usage string) {
+	varE(f, newGenericValue(p, "string", parseSecret, Secret.Value), flagName, envName, value, usage)
+	f.MarkSecret(flagName)
+}
+
+func parseSecret(s string) (Secret, error) { return Secret(s), nil }
+
+// MarkSecret marks the named flags as secret. The values of secret flags are masked
+// in the help output, the command spec and the errors, and the flag values format
+// as the mask. Panics if a flag is not defined.
+func (f *FlagSet) MarkSecret(names ...string) {
+	for _, name := range names {
+		fl := f.Lookup(name)
+		if fl == nil {
+			panic(fmt.Errorf("flag '%s' is not defined", name))
+		}
+
+		m := f.metaFor(name)
+		if m.secret {
+			continue
+		}
+
+		m.secret = true
+		fl.Value = &secretValue{v: fl.Value}
+		fl.DefValue = maskSecret(fl.DefValue)
+	}
+}
+
+// maskSecret returns the mask for the non-empty secret value.
+func maskSecret(s string) string { return tern(s == "", "", secretMask) }
+
+// secretValue implements flag.Value which formats the value as the mask.
+type secretValue struct {
+	v flag.Value
+}
+
+// Set sets the value leaving it as it was before when the value can't be set,
+// since the value can't be restored from its masked string.
+func (s *secretValue) Set(value string) error {
+	saved := s.v.String()
+
+	if err := s.v.Set(value); err != nil {
+		if s.v.String() != saved {
+			//nolint:errcheck // The value has been formatted by the flag value itself.
+			s.v.Set(saved)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *secretValue) String() string {
+	// The flag package calls String on the zero value.
+	if s == nil || s.v == nil {
+		return ""
+	}
+
+	return maskSecret(s.v.String())
+}
+
+func (s *secretValue) Type() string { return flagValueType(&flag.Flag{Value: s.v}) }
+
+// IsBoolFlag allows to give the secret boolean flag without a value.
+func (s *secretValue) IsBoolFlag() bool { return isBoolFlag(s.v) }
+
+// secretError hides the message of the error of parsing the secret value,
+// since the parsers quote the value they fail on. The error is still
+// matched by errors.Is and errors.As.
+type secretError struct {
+	err error
+}
+
+func (e *secretError) Error() string { return "invalid value " + secretMask }
+
+func (e *secretError) Unwrap() error { return e.err }
+
+// rawString returns the unmasked string of the flag value.
+func rawString(v flag.Value) string {
+	if s, ok := v.(*secretValue); ok {
+		return s.v.String()
+	}
+
+	return v.String()
+}
+
+// lookupEnv returns the value of the environment variable together with the name
+// of the variable the value comes from. When the variable is empty, the value is read
+// from the file named by the variable with the _FILE suffix, e.g. DB_PASSWORD_FILE,
+// without the trailing newline. This supports Docker and Kubernetes secrets.
+func lookupEnv(name string) (string, string, error) {
+	if value := os.Getenv(name); value != "" {
+		return value, name, nil
+	}
+
+	fileEnv := name + "_FILE"
+
+	path := os.Getenv(fileEnv)
+	if path == "" {
+		return "", name, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fileEnv, fmt.Errorf("reading file: %w", err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), fileEnv, nil
+}
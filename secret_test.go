@@ -0,0 +1,286 @@
+package scotty
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	type config struct {
+		User     string
+		Password Secret
+	}
+
+	cfg := config{User: "admin", Password: "hunter2"}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q"} {
+		if got := fmt.Sprintf(format, cfg); strings.Contains(got, "hunter2") {
+			t.Errorf("%s: expected the secret to be masked, got %s", format, got)
+		}
+	}
+
+	dump, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"User":"admin","Password":"******"}`; string(dump) != want {
+		t.Errorf("want := %s, got := %s", want, dump)
+	}
+
+	if cfg.Password.Value() != "hunter2" {
+		t.Errorf("want := %v, got := %v", "hunter2", cfg.Password.Value())
+	}
+
+	if got := Secret("").String(); got != "" {
+		t.Errorf("expected empty secret to stay empty, got %q", got)
+	}
+}
+
+func TestBindConfig_Secret(t *testing.T) {
+	type config struct {
+		Password Secret  `flag:"password" env:"TEST_PASSWORD" default:"hunter2" secret:"true" usage:"Password"`
+		Token    Secret  `flag:"token" env:"TEST_TOKEN" usage:"Token"`
+		Key      *Secret `flag:"key" env:"TEST_KEY" usage:"Key"`
+	}
+
+	t.Setenv("TEST_TOKEN", "t0ken")
+	t.Setenv("TEST_KEY", "k3y")
+
+	cfg := &config{}
+	cmd := &Command{
+		Name: "test",
+		Run:  func(cmd *Command, args []string) error { return nil },
+	}
+
+	if err := cmd.BindConfig(cfg); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	if err := cmd.execCommand(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Password.Value() != "hunter2" || cfg.Token.Value() != "t0ken" || cfg.Key == nil || cfg.Key.Value() != "k3y" {
+		t.Errorf("unexpected config: password=%s, token=%s", cfg.Password.Value(), cfg.Token.Value())
+	}
+
+	if dump := fmt.Sprintf("%+v %+v", cfg, *cfg.Key); strings.Contains(dump, "hunter2") ||
+		strings.Contains(dump, "t0ken") || strings.Contains(dump, "k3y") {
+		t.Errorf("expected the secrets to be masked, got %s", dump)
+	}
+
+	for _, name := range []string{"password", "token"} {
+		fl := cmd.Flags().Lookup(name)
+
+		if fl.Value.String() != secretMask {
+			t.Errorf("%s: expected the value to be masked, got %q", name, fl.Value.String())
+		}
+	}
+
+	var b strings.Builder
+
+	if err := cmd.renderHelp(&b, terminal{width: 120}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd.Flags().SetOutput(&b)
+	cmd.Flags().PrintDefaults()
+
+	spec, err := Spec(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.Write(spec)
+
+	if strings.Contains(b.String(), "hunter2") || strings.Contains(b.String(), "t0ken") {
+		t.Errorf("expected the secrets to be masked, got %s", b.String())
+	}
+
+	if want := "Password (default: ******; env: TEST_PASSWORD)"; !strings.Contains(b.String(), want) {
+		t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+	}
+
+	if !strings.Contains(string(spec), `"secret": true`) {
+		t.Errorf("expected the spec to mark the secret flags, got %s", spec)
+	}
+
+	err = cmd.BindConfig(&struct {
+		PIN string `flag:"pin" secret:"true"`
+	}{})
+	if err == nil || !strings.Contains(err.Error(), "scotty.Secret") {
+		t.Errorf("expected error for secret tag on non-Secret field, got %v", err)
+	}
+}
+
+func TestCommand_SecretEnvError(t *testing.T) {
+	helperDisableStdout(t)
+
+	t.Setenv("TEST_REGION", "s3cr3t")
+	t.Setenv("TEST_TAGS", "s3cr3t,\"")
+
+	var (
+		region string
+		tags   []string
+	)
+
+	cmd := &Command{
+		Name: "test",
+		SetFlags: func(flags *FlagSet) {
+			flags.SetStrictEnv(true)
+			flags.EnumVarE(&region, "region", "TEST_REGION", []string{"eu", "us"}, "eu", "Region")
+			flags.StringSliceVarE(&tags, "tags", "TEST_TAGS", nil, "Tags")
+			flags.MarkSecret("region", "tags")
+		},
+		Run: func(cmd *Command, args []string) error { return nil },
+	}
+
+	err := cmd.execCommand(nil)
+	if !errors.Is(err, ErrInvalidEnvValue) {
+		t.Fatalf("expected ErrInvalidEnvValue, got %v", err)
+	}
+
+	var cfgErrs ConfigErrors
+	if !errors.As(err, &cfgErrs) || len(cfgErrs) != 2 {
+		t.Fatalf("expected two configuration errors, got %v", err)
+	}
+
+	for _, e := range cfgErrs {
+		if strings.Contains(e.Error(), "s3cr3t") {
+			t.Errorf("expected the secret to be masked in the error, got %v", e)
+		}
+	}
+
+	if strings.Contains(err.Error(), "s3cr3t") {
+		t.Errorf("expected the secret to be masked in the report, got %v", err)
+	}
+
+	if region != "eu" {
+		t.Errorf("want := %q, got := %q", "eu", region)
+	}
+}
+
+func TestFlagSet_EnvFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "db_password")
+
+	if err := os.WriteFile(path, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("File", func(t *testing.T) {
+		t.Setenv("TEST_DB_PASSWORD_FILE", path)
+
+		f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+
+		var got string
+
+		f.StringVarE(&got, "db-password", "TEST_DB_PASSWORD", "", "")
+
+		if err := f.Parse(nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if got != "s3cret" {
+			t.Errorf("want := %q, got := %q", "s3cret", got)
+		}
+
+		if source, _ := f.Source("db-password"); source != SourceEnv {
+			t.Errorf("want := %v, got := %v", SourceEnv, source)
+		}
+	})
+
+	t.Run("EnvWins", func(t *testing.T) {
+		t.Setenv("TEST_DB_PASSWORD", "direct")
+		t.Setenv("TEST_DB_PASSWORD_FILE", path)
+
+		f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+
+		var got string
+
+		f.StringVarE(&got, "db-password", "TEST_DB_PASSWORD", "", "")
+
+		if err := f.Parse(nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if got != "direct" {
+			t.Errorf("want := %q, got := %q", "direct", got)
+		}
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		t.Setenv("TEST_DB_PASSWORD_FILE", filepath.Join(dir, "missing"))
+
+		f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+		f.SetStrictEnv(true)
+
+		var got string
+
+		f.StringVarE(&got, "db-password", "TEST_DB_PASSWORD", "", "")
+
+		var envErr *EnvParseError
+		if err := f.Parse(nil); !errors.As(err, &envErr) || envErr.Env != "TEST_DB_PASSWORD_FILE" {
+			t.Errorf("expected EnvParseError of the file variable, got %v", err)
+		}
+	})
+
+	t.Run("MissingFileNotStrict", func(t *testing.T) {
+		helperDisableStdout(t)
+
+		t.Setenv("TEST_DB_PASSWORD_FILE", filepath.Join(dir, "missing"))
+
+		var password Secret
+
+		cmd := &Command{
+			Name: "test",
+			SetFlags: func(flags *FlagSet) {
+				flags.SecretVarE(&password, "db-password", "TEST_DB_PASSWORD", "default", "")
+			},
+			Run: func(cmd *Command, args []string) error {
+				t.Error("expected the command not to run")
+				return nil
+			},
+		}
+
+		var envErr *EnvParseError
+		if err := cmd.execCommand(nil); !errors.As(err, &envErr) || envErr.Env != "TEST_DB_PASSWORD_FILE" {
+			t.Errorf("expected EnvParseError of the file variable, got %v", err)
+		}
+	})
+}
+
+func TestCommand_PersistentSecret(t *testing.T) {
+	helperDisableStdout(t)
+
+	var token Secret
+
+	root := &Command{
+		Name: "root",
+		SetPersistentFlags: func(flags *FlagSet) {
+			flags.SecretVar(&token, "token", "", "API token")
+		},
+	}
+
+	sub := &Command{
+		Name: "sub",
+		Run:  func(cmd *Command, args []string) error { return nil },
+	}
+
+	root.AddSubcommands(sub)
+
+	if err := root.execCommand([]string{"-token=abc", "sub"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if token.Value() != "abc" {
+		t.Errorf("want := %q, got := %q", "abc", token.Value())
+	}
+}
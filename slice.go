@@ -0,0 +1,211 @@
+package scotty
+
+import (
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSliceSeparator separates the items of slice flag values.
+const defaultSliceSeparator = ','
+
+// SetSliceSeparator sets the separator of items for the slice and map
+// flags defined after the call. The default separator is a comma.
+// Items containing the separator can be quoted CSV-style: -tag '"a,b",c'.
+// Panics if the separator can't be used to separate CSV fields.
+func (f *FlagSet) SetSliceSeparator(sep rune) {
+	if !validSliceSeparator(sep) {
+		panic(fmt.Errorf("invalid slice separator: %q", sep))
+	}
+
+	f.sliceSep = sep
+}
+
+// StringSliceVar defines a []string flag with specified name, default value, and usage string.
+// The argument p points to a []string variable in which to store the value of the flag.
+// The flag can be repeated and each value can hold several separated items:
+// -tag a -tag b,c gives [a b c]. The first occurrence replaces the default value.
+func (f *FlagSet) StringSliceVar(p *[]string, name string, value []string, usage string) {
+	f.Var(newSliceValue(p, value, f.sliceSeparator(), "string", parseString, formatString), name, usage)
+}
+
+// StringSliceVarE defines a []string flag and environment variable with specified name, default value, and usage string.
+// The argument p points to a []string variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// The environment variable value is split into items the same way as the flag value.
+func (f *FlagSet) StringSliceVarE(p *[]string, flagName, envName string, value []string, usage string) {
+	v := newSliceValue(p, value, f.sliceSeparator(), "string", parseString, formatString)
+	f.envVar(v, flagName, envName, usage)
+}
+
+// IntSliceVar defines a []int flag with specified name, default value, and usage string.
+// The argument p points to a []int variable in which to store the value of the flag.
+// The flag can be repeated and each value can hold several separated items:
+// -port 80 -port 443,8080 gives [80 443 8080]. The first occurrence replaces the default value.
+func (f *FlagSet) IntSliceVar(p *[]int, name string, value []int, usage string) {
+	f.Var(newSliceValue(p, value, f.sliceSeparator(), "int", strconv.Atoi, strconv.Itoa), name, usage)
+}
+
+// IntSliceVarE defines a []int flag and environment variable with specified name, default value, and usage string.
+// The argument p points to a []int variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+func (f *FlagSet) IntSliceVarE(p *[]int, flagName, envName string, value []int, usage string) {
+	v := newSliceValue(p, value, f.sliceSeparator(), "int", strconv.Atoi, strconv.Itoa)
+	f.envVar(v, flagName, envName, usage)
+}
+
+// DurationSliceVar defines a []time.Duration flag with specified name, default value, and usage string.
+// The argument p points to a []time.Duration variable in which to store the value of the flag.
+// The flag can be repeated and each value can hold several separated items:
+// -retry 1s -retry 5s,10s gives [1s 5s 10s]. The first occurrence replaces the default value.
+func (f *FlagSet) DurationSliceVar(p *[]time.Duration, name string, value []time.Duration, usage string) {
+	f.Var(newSliceValue(p, value, f.sliceSeparator(), "duration", time.ParseDuration, time.Duration.String), name, usage)
+}
+
+// DurationSliceVarE defines a []time.Duration flag and environment variable with specified name, default value,
+// and usage string. The argument p points to a []time.Duration variable in which to store the value of the flag
+// or environment variable. Flag has priority over environment variable. If flag not set the environment variable
+// value is set by Parse. If the value of environment variable can't be parsed to destination type the default value
+// will be used.
+func (f *FlagSet) DurationSliceVarE(p *[]time.Duration, flagName, envName string, value []time.Duration, usage string) {
+	v := newSliceValue(p, value, f.sliceSeparator(), "duration", time.ParseDuration, time.Duration.String)
+	f.envVar(v, flagName, envName, usage)
+}
+
+// sliceValue implements flag.Value for slices of any type.
+type sliceValue[T any] struct {
+	p        *[]T
+	sep      rune
+	typeName string
+	parse    func(string) (T, error)
+	format   func(T) string
+
+	// changed reports whether the value has been set at least once.
+	// The first Set replaces the default value, the next ones append to it.
+	changed bool
+}
+
+func newSliceValue[T any](
+	p *[]T,
+	value []T,
+	sep rune,
+	typeName string,
+	parse func(string) (T, error),
+	format func(T) string,
+) *sliceValue[T] {
+	*p = slices.Clone(value)
+
+	return &sliceValue[T]{
+		p:        p,
+		sep:      sep,
+		typeName: typeName,
+		parse:    parse,
+		format:   format,
+	}
+}
+
+func (v *sliceValue[T]) Set(s string) error {
+	items, err := splitSlice(s, v.sep)
+	if err != nil {
+		return err
+	}
+
+	parsed := make([]T, 0, len(items))
+
+	for _, item := range items {
+		p, err := v.parse(item)
+		if err != nil {
+			return fmt.Errorf("invalid item %q: %w", item, err)
+		}
+
+		parsed = append(parsed, p)
+	}
+
+	if !v.changed {
+		*v.p = parsed
+		v.changed = true
+
+		return nil
+	}
+
+	*v.p = append(*v.p, parsed...)
+
+	return nil
+}
+
+func (v *sliceValue[T]) String() string {
+	// The flag package calls String on the zero value.
+	if v == nil || v.p == nil {
+		return ""
+	}
+
+	items := make([]string, 0, len(*v.p))
+	for _, item := range *v.p {
+		items = append(items, v.format(item))
+	}
+
+	return joinSlice(items, v.sep)
+}
+
+func (v *sliceValue[T]) Type() string { return "[]" + v.typeName }
+
+// sliceSeparator returns the separator for newly defined slice flags.
+func (f *FlagSet) sliceSeparator() rune {
+	return tern(f.sliceSep != 0, f.sliceSep, defaultSliceSeparator)
+}
+
+// splitSlice splits s into items separated by sep.
+// Items can be quoted with double quotes as in CSV.
+func splitSlice(s string, sep rune) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	r := csv.NewReader(strings.NewReader(s))
+	r.Comma = sep
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("split %q: %w", s, err)
+	}
+
+	var items []string
+	for _, record := range records {
+		items = append(items, record...)
+	}
+
+	return items, nil
+}
+
+// joinSlice joins items with sep quoting them as in CSV when needed.
+func joinSlice(items []string, sep rune) string {
+	if len(items) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	w := csv.NewWriter(&b)
+	w.Comma = sep
+
+	//nolint:errcheck // Writing to strings.Builder never fails.
+	w.Write(items)
+	w.Flush()
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// validSliceSeparator reports whether sep can separate CSV fields.
+func validSliceSeparator(sep rune) bool {
+	return sep != 0 && sep != '"' && sep != '\r' && sep != '\n' && sep != 0xFFFD
+}
+
+func parseString(s string) (string, error) { return s, nil }
+
+func formatString(s string) string { return s }
@@ -0,0 +1,222 @@
+package scotty
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_StringSliceVarE(t *testing.T) {
+	type tcase struct {
+		want   []string
+		before func(t *testing.T, f *FlagSet, got *[]string)
+		args   []string
+	}
+
+	tests := map[string]tcase{
+		"Repeated": {
+			want: []string{"a", "b"},
+			before: func(t *testing.T, f *FlagSet, got *[]string) {
+				t.Helper()
+				f.StringSliceVarE(got, "tag", "TEST_TAGS", []string{"def"}, "")
+			},
+			args: []string{"-tag", "a", "-tag", "b"},
+		},
+
+		"Separated": {
+			want: []string{"a", "b", "c"},
+			before: func(t *testing.T, f *FlagSet, got *[]string) {
+				t.Helper()
+				f.StringSliceVarE(got, "tag", "TEST_TAGS", nil, "")
+			},
+			args: []string{"-tag", "a, b", "-tag", "c"},
+		},
+
+		"Quoted": {
+			want: []string{"a,b", "c"},
+			before: func(t *testing.T, f *FlagSet, got *[]string) {
+				t.Helper()
+				f.StringSliceVarE(got, "tag", "TEST_TAGS", nil, "")
+			},
+			args: []string{"-tag", `"a,b",c`},
+		},
+
+		"Custom separator": {
+			want: []string{"a,b", "c"},
+			before: func(t *testing.T, f *FlagSet, got *[]string) {
+				t.Helper()
+				f.SetSliceSeparator(';')
+				f.StringSliceVarE(got, "tag", "TEST_TAGS", nil, "")
+			},
+			args: []string{"-tag", "a,b;c"},
+		},
+
+		"Env": {
+			want: []string{"x", "y"},
+			before: func(t *testing.T, f *FlagSet, got *[]string) {
+				t.Helper()
+				t.Setenv("TEST_TAGS", "x,y")
+				f.StringSliceVarE(got, "tag", "TEST_TAGS", []string{"def"}, "")
+			},
+			args: []string{},
+		},
+
+		"BothSet": {
+			want: []string{"a"},
+			before: func(t *testing.T, f *FlagSet, got *[]string) {
+				t.Helper()
+				t.Setenv("TEST_TAGS", "x,y")
+				f.StringSliceVarE(got, "tag", "TEST_TAGS", []string{"def"}, "")
+			},
+			args: []string{"-tag=a"},
+		},
+
+		"InvalidEnv": {
+			want: []string{"def"},
+			before: func(t *testing.T, f *FlagSet, got *[]string) {
+				t.Helper()
+				t.Setenv("TEST_TAGS", `"x`)
+				f.StringSliceVarE(got, "tag", "TEST_TAGS", []string{"def"}, "")
+			},
+			args: []string{},
+		},
+
+		"Default": {
+			want: []string{"def"},
+			before: func(t *testing.T, f *FlagSet, got *[]string) {
+				t.Helper()
+				f.StringSliceVarE(got, "tag", "TEST_TAGS", []string{"def"}, "")
+			},
+			args: []string{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+
+			var got []string
+
+			tc.before(t, f, &got)
+
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("want := %v, got := %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFlagSet_IntSliceVarE(t *testing.T) {
+	t.Setenv("TEST_PORTS", "80,lalala")
+
+	f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+	f.SetOutput(discard{})
+
+	var got []int
+
+	f.IntSliceVarE(&got, "port", "TEST_PORTS", []int{8080}, "")
+
+	if !reflect.DeepEqual(got, []int{8080}) {
+		t.Errorf("want := %v, got := %v", []int{8080}, got)
+	}
+
+	if err := f.Parse([]string{"-port", "80,443", "-port=8443"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int{80, 443, 8443}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want := %v, got := %v", want, got)
+	}
+
+	if err := f.Parse([]string{"-port", "1,x"}); err == nil {
+		t.Error("expected error for invalid item, got nil")
+	}
+}
+
+func TestFlagSet_DurationSliceVarE(t *testing.T) {
+	t.Setenv("TEST_RETRY", "1s,5s")
+
+	f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+
+	var got []time.Duration
+
+	f.DurationSliceVarE(&got, "retry", "TEST_RETRY", nil, "")
+
+	if err := f.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []time.Duration{time.Second, 5 * time.Second}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want := %v, got := %v", want, got)
+	}
+
+	if typ := flagValueType(f.Lookup("retry")); typ != "[]duration" {
+		t.Errorf("type := %q, want %q", typ, "[]duration")
+	}
+
+	if source, _ := f.Source("retry"); source != SourceEnv {
+		t.Errorf("source := %v, want %v", source, SourceEnv)
+	}
+}
+
+func TestCommand_PersistentSliceFlag(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want []string
+	}{
+		"Default":          {args: []string{"sub"}, want: []string{"x"}},
+		"Subcommand":       {args: []string{"sub", "-tag", "b"}, want: []string{"b"}},
+		"Parent":           {args: []string{"-tag", "a", "sub"}, want: []string{"a"}},
+		"ParentSubcommand": {args: []string{"-tag", "a", "sub", "-tag", "b"}, want: []string{"a", "b"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			helperDisableStdout(t)
+
+			var tags, got []string
+
+			root := &Command{
+				Name: "app",
+				SetPersistentFlags: func(flags *FlagSet) {
+					flags.StringSliceVar(&tags, "tag", []string{"x"}, "")
+				},
+			}
+
+			root.AddSubcommands(&Command{
+				Name: "sub",
+				Run: func(cmd *Command, args []string) error {
+					got = tags
+					return nil
+				},
+			})
+
+			if err := root.execCommand(tc.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("want := %v, got := %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func Test_joinSlice(t *testing.T) {
+	if got := joinSlice([]string{"a,b", "c"}, ','); got != `"a,b",c` {
+		t.Errorf("want := %q, got := %q", `"a,b",c`, got)
+	}
+
+	if got := joinSlice(nil, ','); got != "" {
+		t.Errorf("want := %q, got := %q", "", got)
+	}
+}
+
+type discard struct{}
+
+func (discard) Write(p []byte) (int, error) { return len(p), nil }
@@ -0,0 +1,80 @@
+package scotty
+
+import "sync"
+
+// Source is the source the flag value comes from.
+type Source int
+
+const (
+	// SourceDefault means the flag holds its default value.
+	SourceDefault Source = iota
+
+	// SourceEnv means the flag value comes from the environment variable.
+	SourceEnv
+
+	// SourceDotenv means the flag value comes from the environment variable
+	// which has been set by LoadDotenv or LoadDotenvOverride.
+	SourceDotenv
+
+	// SourceFlag means the flag value has been given in the command line.
+	SourceFlag
+)
+
+// String returns the name of the source.
+func (s Source) String() string {
+	switch s {
+	case SourceEnv:
+		return "env"
+
+	case SourceDotenv:
+		return "dotenv"
+
+	case SourceFlag:
+		return "flag"
+
+	default:
+		return "default"
+	}
+}
+
+// Source returns the source of the named flag value. The source is known
+// after parsing, before that all the flags hold their default values.
+// Returns false if the flag is not defined.
+func (f *FlagSet) Source(name string) (Source, bool) {
+	if f.Lookup(name) == nil {
+		return SourceDefault, false
+	}
+
+	return f.lookupMeta(name).source, true
+}
+
+// Changed reports whether the named flag value has been provided
+// in the command line or by the environment variable, even when
+// the provided value equals the default one.
+func (f *FlagSet) Changed(name string) bool {
+	source, ok := f.Source(name)
+
+	return ok && source != SourceDefault
+}
+
+// dotenvKeys holds the names of the environment variables set from dotenv files.
+var dotenvKeys = struct {
+	sync.RWMutex
+	keys map[string]bool
+}{keys: make(map[string]bool)}
+
+// markDotenvKey records that the environment variable has been set from a dotenv file.
+func markDotenvKey(key string) {
+	dotenvKeys.Lock()
+	defer dotenvKeys.Unlock()
+
+	dotenvKeys.keys[key] = true
+}
+
+// envSource returns the source of the value of the environment variable.
+func envSource(key string) Source {
+	dotenvKeys.RLock()
+	defer dotenvKeys.RUnlock()
+
+	return tern(dotenvKeys.keys[key], SourceDotenv, SourceEnv)
+}
@@ -0,0 +1,229 @@
+package scotty
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFlagSet_Source(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+
+	if err := os.WriteFile(path, []byte("TEST_SOURCE_DOTENV=dotenv\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Register the cleanup of the variable set by LoadDotenv.
+	t.Setenv("TEST_SOURCE_DOTENV", "")
+
+	if err := os.Unsetenv("TEST_SOURCE_DOTENV"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadDotenv(path); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("TEST_SOURCE_ENV", "env")
+	t.Setenv("TEST_SOURCE_BOTH", "env")
+	t.Setenv("TEST_SOURCE_INVALID", "lalala")
+
+	f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+
+	var (
+		def, fromFlag, fromEnv, fromDotenv, both string
+		invalid                                  int
+	)
+
+	f.StringVarE(&def, "default", "TEST_SOURCE_UNSET", "value", "")
+	f.StringVarE(&fromFlag, "flag", "", "value", "")
+	f.StringVarE(&fromEnv, "env", "TEST_SOURCE_ENV", "value", "")
+	f.StringVarE(&fromDotenv, "dotenv", "TEST_SOURCE_DOTENV", "value", "")
+	f.StringVarE(&both, "both", "TEST_SOURCE_BOTH", "value", "")
+	f.IntVarE(&invalid, "invalid", "TEST_SOURCE_INVALID", 1, "")
+
+	if err := f.Parse([]string{"-flag=value", "-both=flag"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		source  Source
+		changed bool
+	}{
+		"default": {source: SourceDefault, changed: false},
+		"flag":    {source: SourceFlag, changed: true},
+		"env":     {source: SourceEnv, changed: true},
+		"dotenv":  {source: SourceDotenv, changed: true},
+		"both":    {source: SourceFlag, changed: true},
+		"invalid": {source: SourceDefault, changed: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			source, ok := f.Source(name)
+			if !ok {
+				t.Fatalf("expected flag %q to be defined", name)
+			}
+
+			if source != tc.source {
+				t.Errorf("want := %v, got := %v", tc.source, source)
+			}
+
+			if changed := f.Changed(name); changed != tc.changed {
+				t.Errorf("want := %v, got := %v", tc.changed, changed)
+			}
+		})
+	}
+
+	if _, ok := f.Source("undefined"); ok {
+		t.Error("expected undefined flag to have no source")
+	}
+
+	if both != "flag" {
+		t.Errorf("want := %v, got := %v", "flag", both)
+	}
+
+	if invalid != 1 {
+		t.Errorf("want := %v, got := %v", 1, invalid)
+	}
+}
+
+func TestCommand_SourceOfPersistentFlag(t *testing.T) {
+	helperDisableStdout(t)
+
+	t.Setenv("TEST_VERBOSE", "false")
+
+	var (
+		verbose bool
+		source  Source
+	)
+
+	root := &Command{
+		Name: "root",
+		SetPersistentFlags: func(flags *FlagSet) {
+			flags.BoolVarE(&verbose, "verbose", "TEST_VERBOSE", false, "verbose output")
+		},
+	}
+
+	sub := &Command{
+		Name: "sub",
+		Run: func(cmd *Command, args []string) error {
+			source, _ = cmd.Flags().Source("verbose")
+			return nil
+		},
+	}
+
+	root.AddSubcommands(sub)
+
+	if err := root.execCommand([]string{"-verbose", "sub"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !verbose {
+		t.Error("expected the flag parsed by the parent to win over the environment variable")
+	}
+
+	if source != SourceFlag {
+		t.Errorf("want := %v, got := %v", SourceFlag, source)
+	}
+}
+
+func TestBindConfig_RequiredProvided(t *testing.T) {
+	type config struct {
+		Port int    `flag:"port" env:"TEST_PORT" required:"true" usage:"Port"`
+		Host string `flag:"host" default:"localhost" required:"true" usage:"Host"`
+	}
+
+	tests := map[string]struct {
+		args    []string
+		env     string
+		wantErr bool
+	}{
+		"ZeroValueFlag":  {args: []string{"-port=0", "-host=localhost"}},
+		"ZeroValueEnv":   {args: []string{"-host=localhost"}, env: "0"},
+		"DefaultOnly":    {args: []string{"-port=8080"}, wantErr: true},
+		"NothingGiven":   {args: []string{}, wantErr: true},
+		"BothFlagsGiven": {args: []string{"-port=8080", "-host=example.com"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			helperDisableStdout(t)
+
+			if tc.env != "" {
+				t.Setenv("TEST_PORT", tc.env)
+			}
+
+			cmd := &Command{
+				Name: "test",
+				Run:  func(cmd *Command, args []string) error { return nil },
+			}
+
+			if err := cmd.BindConfig(&config{}); err != nil {
+				t.Fatal(err)
+			}
+
+			err := cmd.execCommand(tc.args)
+
+			var reqErr *RequiredFieldError
+			if got := errors.As(err, &reqErr); got != tc.wantErr {
+				t.Errorf("want error := %v, got := %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFlagSet_SourceOfNegatedFlag(t *testing.T) {
+	type config struct {
+		Cache bool   `flag:"cache" env:"TEST_CACHE" default:"true" required:"true" negatable:"true" usage:"Use cache"`
+		Store string `flag:"store" usage:"Store address"`
+	}
+
+	tests := map[string]struct {
+		args       []string
+		env        string
+		wantCache  bool
+		wantSource Source
+		wantErr    error
+	}{
+		"NegationOverEnv":   {args: []string{"-no-cache"}, env: "true", wantSource: SourceFlag},
+		"NegationRequired":  {args: []string{"-no-cache"}, wantSource: SourceFlag},
+		"NothingGiven":      {wantCache: true, wantSource: SourceDefault, wantErr: ErrRequiredField},
+		"NegationExclusive": {args: []string{"-no-cache", "-store=redis"}, wantSource: SourceFlag, wantErr: ErrMutuallyExclusive},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			helperDisableStdout(t)
+
+			t.Setenv("TEST_CACHE", tc.env)
+
+			cfg := &config{}
+			cmd := &Command{
+				Name: "test",
+				Run:  func(cmd *Command, args []string) error { return nil },
+			}
+
+			if err := cmd.BindConfig(cfg); err != nil {
+				t.Fatal(err)
+			}
+
+			cmd.Flags().MarkMutuallyExclusive("cache", "store")
+
+			if err := cmd.execCommand(tc.args); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("want error := %v, got := %v", tc.wantErr, err)
+			}
+
+			if cfg.Cache != tc.wantCache {
+				t.Errorf("want := %v, got := %v", tc.wantCache, cfg.Cache)
+			}
+
+			if source, _ := cmd.Flags().Source("cache"); source != tc.wantSource {
+				t.Errorf("want := %v, got := %v", tc.wantSource, source)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package scotty
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+)
+
+// SpecVersion is the version of the JSON schema produced by Spec.
+// It is incremented on every incompatible change of the schema.
+const SpecVersion = 1
+
+// AppSpec is the machine-readable description of the whole command tree.
+type AppSpec struct {
+	// Version holds the version of the schema. See SpecVersion.
+	Version int `json:"version"`
+
+	// Root holds the description of the root command.
+	Root CommandSpec `json:"root"`
+}
+
+// CommandSpec is the machine-readable description of a command.
+type CommandSpec struct {
+	// Name holds the name of the command.
+	Name string `json:"name"`
+
+	// Path holds the chain of command names from the root
+	// to the command separated by spaces, e.g. "app db migrate".
+	Path string `json:"path"`
+
+	// Short holds the short description of the command.
+	Short string `json:"short,omitempty"`
+
+	// Long holds the long description of the command.
+	Long string `json:"long,omitempty"`
+
+	// Args holds the description of the positional arguments.
+	Args string `json:"args,omitempty"`
+
+	// Example holds the examples of the command usage.
+	Example string `json:"example,omitempty"`
+
+	// Group holds the ID of the command group the command belongs to.
+	Group string `json:"group,omitempty"`
+
+	// Hidden reports whether the command is hidden from the help output.
+	Hidden bool `json:"hidden,omitempty"`
+
+	// Deprecated holds the deprecation message of the command.
+	Deprecated string `json:"deprecated,omitempty"`
+
+	// Flags holds the flags of the command, including inherited ones.
+	Flags []FlagSpec `json:"flags,omitempty"`
+
+	// Constraints holds the relationship constraints of the flags.
+	Constraints []ConstraintSpec `json:"constraints,omitempty"`
+
+	// Commands holds the subcommands sorted by name.
+	Commands []CommandSpec `json:"commands,omitempty"`
+}
+
+// FlagSpec is the machine-readable description of a flag.
+type FlagSpec struct {
+	// Name holds the name of the flag without the leading dash.
+	Name string `json:"name"`
+
+	// Type holds the type name of the flag value, e.g. "duration".
+	Type string `json:"type"`
+
+	// Placeholder holds the name shown instead of the type in the help output.
+	Placeholder string `json:"placeholder,omitempty"`
+
+	// Usage holds the usage string of the flag.
+	Usage string `json:"usage,omitempty"`
+
+	// Default holds the default value of the flag as text.
+	Default string `json:"default,omitempty"`
+
+	// Env holds the name of the environment variable bound to the flag.
+	Env string `json:"env,omitempty"`
+
+	// Required reports whether the flag must be provided.
+	Required bool `json:"required,omitempty"`
+
+	// Optional reports whether the flag value stays unset unless the flag is provided.
+	Optional bool `json:"optional,omitempty"`
+
+	// Secret reports whether the flag value is masked in the output.
+	Secret bool `json:"secret,omitempty"`
+
+	// Enum holds the allowed values of the flag.
+	Enum []string `json:"enum,omitempty"`
+
+	// Rules holds the descriptions of the validation rules of the flag value.
+	Rules []string `json:"rules,omitempty"`
+
+	// Negation holds the name of the flag which negates the boolean flag.
+	Negation string `json:"negation,omitempty"`
+
+	// Inherited reports whether the flag is a persistent flag
+	// defined by one of the ancestor commands.
+	Inherited bool `json:"inherited,omitempty"`
+
+	// Group holds the name of the flag group.
+	Group string `json:"group,omitempty"`
+
+	// Hidden reports whether the flag is hidden from the help output.
+	Hidden bool `json:"hidden,omitempty"`
+
+	// Deprecated holds the deprecation message of the flag.
+	Deprecated string `json:"deprecated,omitempty"`
+}
+
+// ConstraintSpec is the machine-readable description of a relationship constraint of flags.
+type ConstraintSpec struct {
+	// Kind holds the kind of the constraint: "mutually_exclusive",
+	// "required_together" or "one_required".
+	Kind string `json:"kind"`
+
+	// Flags holds the names of the flags of the constraint.
+	Flags []string `json:"flags"`
+}
+
+// Spec serializes the whole command tree of the root command into JSON
+// described by AppSpec. Unlike the help output, the spec includes
+// hidden commands and flags, marking them as hidden, which makes it
+// suitable for generating documentation, IDE integrations and wrappers.
+func Spec(root *Command) ([]byte, error) {
+	spec := AppSpec{
+		Version: SpecVersion,
+		Root:    commandSpec(root),
+	}
+
+	data, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal spec: %w", err)
+	}
+
+	return data, nil
+}
+
+// commandSpec describes the command and all its subcommands.
+func commandSpec(c *Command) CommandSpec {
+	spec := CommandSpec{
+		Name:       c.Name,
+		Path:       commandsChain(c),
+		Short:      c.Short,
+		Long:       c.Long,
+		Args:       c.ArgsUsage,
+		Example:    c.Example,
+		Group:      c.Group,
+		Hidden:     c.Hidden,
+		Deprecated: c.Deprecated,
+	}
+
+	flags := c.Flags()
+
+	flags.VisitAll(func(f *flag.Flag) {
+		spec.Flags = append(spec.Flags, flagSpec(flags, f))
+	})
+
+	for _, constraint := range flags.constraints {
+		spec.Constraints = append(spec.Constraints, ConstraintSpec{
+			Kind:  constraint.kind.String(),
+			Flags: constraint.names,
+		})
+	}
+
+	for _, cmd := range sortedSubcommands(c.subcommands) {
+		spec.Commands = append(spec.Commands, commandSpec(cmd))
+	}
+
+	return spec
+}
+
+// flagSpec describes the flag of the flag set.
+func flagSpec(flags *FlagSet, f *flag.Flag) FlagSpec {
+	m := flags.lookupMeta(f.Name)
+	placeholder, usage := flagPlaceholder(flags, f)
+
+	return FlagSpec{
+		Name:        f.Name,
+		Type:        flagValueType(f),
+		Placeholder: placeholder,
+		Usage:       usage,
+		Default:     f.DefValue,
+		Env:         flags.envName(f.Name),
+		Required:    m.required,
+		Optional:    m.optional,
+		Secret:      m.secret,
+		Enum:        m.enum,
+		Rules:       m.rules,
+		Negation:    m.negation,
+		Inherited:   m.inherited,
+		Group:       m.group,
+		Hidden:      m.hidden,
+		Deprecated:  m.deprecated,
+	}
+}
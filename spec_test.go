@@ -0,0 +1,97 @@
+package scotty
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSpec(t *testing.T) {
+	type config struct {
+		Host  string `flag:"host" env:"APP_HOST" default:"localhost" placeholder:"HOST" group:"Server" usage:"server host"`
+		Token string `flag:"token" env:"APP_TOKEN" required:"true" usage:"auth token"`
+		Trace bool   `flag:"trace" hidden:"true" usage:"trace internals"`
+	}
+
+	root := &Command{
+		Name:  "app",
+		Short: "The app",
+		SetPersistentFlags: func(flags *FlagSet) {
+			flags.Bool("verbose", false, "verbose output")
+		},
+	}
+
+	serve := &Command{
+		Name:       "serve",
+		Short:      "Start the server",
+		ArgsUsage:  "<dir>",
+		Group:      "main",
+		Deprecated: "use run",
+	}
+
+	debug := &Command{Name: "debug", Hidden: true}
+
+	root.AddSubcommands(serve, debug)
+
+	if err := serve.BindConfig(&config{}); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	data, err := Spec(root)
+	if err != nil {
+		t.Fatalf("Spec failed: %v", err)
+	}
+
+	var got AppSpec
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	verbose := FlagSpec{Name: "verbose", Type: "bool", Usage: "verbose output", Default: "false"}
+	inheritedVerbose := verbose
+	inheritedVerbose.Inherited = true
+
+	want := AppSpec{
+		Version: SpecVersion,
+		Root: CommandSpec{
+			Name:  "app",
+			Path:  "app",
+			Short: "The app",
+			Flags: []FlagSpec{verbose},
+			Commands: []CommandSpec{
+				{
+					Name:   "debug",
+					Path:   "app debug",
+					Hidden: true,
+					Flags:  []FlagSpec{inheritedVerbose},
+				},
+				{
+					Name:       "serve",
+					Path:       "app serve",
+					Short:      "Start the server",
+					Args:       "<dir>",
+					Group:      "main",
+					Deprecated: "use run",
+					Flags: []FlagSpec{
+						{
+							Name:        "host",
+							Type:        "string",
+							Placeholder: "HOST",
+							Usage:       "server host",
+							Default:     "localhost",
+							Env:         "APP_HOST",
+							Group:       "Server",
+						},
+						{Name: "token", Type: "string", Usage: "auth token", Env: "APP_TOKEN", Required: true},
+						{Name: "trace", Type: "bool", Usage: "trace internals", Default: "false", Hidden: true},
+						inheritedVerbose,
+					},
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected := %+v, got := %+v", want, got)
+	}
+}
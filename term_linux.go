@@ -0,0 +1,36 @@
+//go:build linux
+
+package scotty
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// winsize mirrors the struct winsize of the TIOCGWINSZ ioctl.
+type winsize struct {
+	row, col       uint16
+	xpixel, ypixel uint16
+}
+
+// isTerminal reports whether the file descriptor refers to a terminal.
+func isTerminal(fd uintptr) bool {
+	var termios syscall.Termios
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCGETS, uintptr(unsafe.Pointer(&termios)))
+
+	return errno == 0
+}
+
+// terminalWidth returns the width in columns of the terminal
+// referred by the file descriptor.
+func terminalWidth(fd uintptr) (int, bool) {
+	var ws winsize
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 || ws.col == 0 {
+		return 0, false
+	}
+
+	return int(ws.col), true
+}
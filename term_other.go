@@ -0,0 +1,12 @@
+//go:build !linux
+
+package scotty
+
+// isTerminal reports whether the file descriptor refers to a terminal.
+// Terminal detection is supported only on Linux.
+func isTerminal(uintptr) bool { return false }
+
+// terminalWidth returns the width in columns of the terminal
+// referred by the file descriptor. Terminal detection is
+// supported only on Linux.
+func terminalWidth(uintptr) (int, bool) { return 0, false }
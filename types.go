@@ -0,0 +1,412 @@
+package scotty
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VarE defines a flag of any type and environment variable with specified name, default value, usage string
+// and parse function which converts the text of the flag or environment variable into the value.
+// The argument p points to a variable in which to store the value of the flag or environment variable.
+// Flag has priority over environment variable. If flag not set the environment variable value is set by Parse.
+// If the value of environment variable can't be parsed to destination type the default value will be used.
+// The value is shown in the help output as formatted by fmt.Sprint.
+func VarE[T any](f *FlagSet, p *T, name, env string, def T, usage string, parse func(string) (T, error)) {
+	varE(f, newGenericValue(p, typeNameOf(reflect.TypeFor[T]()), parse, formatAny[T]), name, env, def, usage)
+}
+
+// RegisterType registers the parse and format functions of the type T,
+// so BindConfig can bind the struct fields of the type T, of the type []T
+// and of the type map[string]T. The format function converts the value
+// to the text shown in the help output. Registering the type again,
+// including the built-in one, replaces the previous registration.
+// The type name shown in the help output is the lowercased name
+// of the type, e.g. "loglevel", which can be overridden by the
+// placeholder struct tag.
+func RegisterType[T any](parse func(string) (T, error), format func(T) string) {
+	typeName := typeNameOf(reflect.TypeFor[T]())
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.binders[reflect.TypeFor[T]()] = func(f *FlagSet, ptr any, opts fieldOpts) error {
+		p, err := fieldPtr[T](ptr)
+		if err != nil {
+			return err
+		}
+
+		v := newGenericValue(p, typeName, parse, format)
+		varE(f, v, opts.flagName, opts.envName, parseDefault(opts.defaultVal, parse), opts.usage)
+
+		return nil
+	}
+
+	registry.binders[reflect.TypeFor[[]T]()] = sliceBinder(typeName, parse, format)
+	registry.binders[reflect.TypeFor[map[string]T]()] = mapBinder(typeName, parse, format)
+}
+
+// typeBinder defines the flag for the struct field which ptr points to.
+type typeBinder func(f *FlagSet, ptr any, opts fieldOpts) error
+
+// registry holds the binders of the types supported by BindConfig.
+var registry = struct {
+	sync.RWMutex
+	binders map[reflect.Type]typeBinder
+}{
+	binders: map[reflect.Type]typeBinder{
+		reflect.TypeFor[string]():         scalarBinder(parseString, (*FlagSet).StringVarE),
+		reflect.TypeFor[bool]():           scalarBinder(strconv.ParseBool, (*FlagSet).BoolVarE),
+		reflect.TypeFor[int]():            scalarBinder(strconv.Atoi, (*FlagSet).IntVarE),
+		reflect.TypeFor[int64]():          scalarBinder(parseInt64, (*FlagSet).Int64VarE),
+		reflect.TypeFor[uint]():           scalarBinder(parseUint, (*FlagSet).UintVarE),
+		reflect.TypeFor[uint64]():         scalarBinder(parseUint64, (*FlagSet).Uint64VarE),
+		reflect.TypeFor[float64]():        scalarBinder(parseFloat64, (*FlagSet).Float64VarE),
+		reflect.TypeFor[time.Duration]():  scalarBinder(time.ParseDuration, (*FlagSet).DurationVarE),
+		reflect.TypeFor[int8]():           scalarBinder(parseSigned[int8], (*FlagSet).Int8VarE),
+		reflect.TypeFor[int16]():          scalarBinder(parseSigned[int16], (*FlagSet).Int16VarE),
+		reflect.TypeFor[int32]():          scalarBinder(parseSigned[int32], (*FlagSet).Int32VarE),
+		reflect.TypeFor[uint8]():          scalarBinder(parseUnsigned[uint8], (*FlagSet).Uint8VarE),
+		reflect.TypeFor[uint16]():         scalarBinder(parseUnsigned[uint16], (*FlagSet).Uint16VarE),
+		reflect.TypeFor[uint32]():         scalarBinder(parseUnsigned[uint32], (*FlagSet).Uint32VarE),
+		reflect.TypeFor[float32]():        scalarBinder(parseFloat32, (*FlagSet).Float32VarE),
+		reflect.TypeFor[time.Time]():      bindTimeField,
+		reflect.TypeFor[*url.URL]():       scalarBinder(url.Parse, (*FlagSet).URLVarE),
+		reflect.TypeFor[netip.Addr]():     scalarBinder(netip.ParseAddr, (*FlagSet).AddrVarE),
+		reflect.TypeFor[netip.AddrPort](): scalarBinder(netip.ParseAddrPort, (*FlagSet).AddrPortVarE),
+		reflect.TypeFor[netip.Prefix]():   scalarBinder(netip.ParsePrefix, (*FlagSet).PrefixVarE),
+		reflect.TypeFor[*regexp.Regexp](): scalarBinder(regexp.Compile, (*FlagSet).RegexpVarE),
+		reflect.TypeFor[os.FileMode]():    scalarBinder(parseFileMode, (*FlagSet).FileModeVarE),
+		reflect.TypeFor[ByteSize]():       scalarBinder(ParseByteSize, (*FlagSet).ByteSizeVarE),
+		reflect.TypeFor[Secret]():         scalarBinder(parseSecret, (*FlagSet).SecretVarE),
+		reflect.TypeFor[[]byte]():         bindBytesField,
+
+		reflect.TypeFor[[]string]():        sliceBinder("string", parseString, formatString),
+		reflect.TypeFor[[]int]():           sliceBinder("int", strconv.Atoi, strconv.Itoa),
+		reflect.TypeFor[[]time.Duration](): sliceBinder("duration", time.ParseDuration, time.Duration.String),
+
+		reflect.TypeFor[map[string]string]():        mapBinder("string", parseString, formatString),
+		reflect.TypeFor[map[string]int]():           mapBinder("int", strconv.Atoi, strconv.Itoa),
+		reflect.TypeFor[map[string]time.Duration](): mapBinder("duration", time.ParseDuration, time.Duration.String),
+	},
+}
+
+// lookupBinder returns the binder registered for the type.
+func lookupBinder(t reflect.Type) (typeBinder, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	binder, ok := registry.binders[t]
+
+	return binder, ok
+}
+
+// scalarBinder returns the binder which defines the flag using one of
+// the *VarE methods of FlagSet. An invalid default value is ignored.
+func scalarBinder[T any](
+	parse func(string) (T, error),
+	define func(f *FlagSet, p *T, flagName, envName string, value T, usage string),
+) typeBinder {
+	return func(f *FlagSet, ptr any, opts fieldOpts) error {
+		p, err := fieldPtr[T](ptr)
+		if err != nil {
+			return err
+		}
+
+		define(f, p, opts.flagName, opts.envName, parseDefault(opts.defaultVal, parse), opts.usage)
+
+		return nil
+	}
+}
+
+// bindTimeField defines the time.Time flag using the layout from the field options.
+func bindTimeField(f *FlagSet, ptr any, opts fieldOpts) error {
+	p, err := fieldPtr[time.Time](ptr)
+	if err != nil {
+		return err
+	}
+
+	layout := tern(opts.layout != "", opts.layout, time.RFC3339)
+	f.TimeVarE(p, opts.flagName, opts.envName, layout, parseDefault(opts.defaultVal, parseTime(layout)), opts.usage)
+
+	return nil
+}
+
+// bindBytesField defines the []byte flag using the encoding from the field options.
+func bindBytesField(f *FlagSet, ptr any, opts fieldOpts) error {
+	p, err := fieldPtr[[]byte](ptr)
+	if err != nil {
+		return err
+	}
+
+	switch opts.encoding {
+	case "", "base64":
+		f.BytesBase64VarE(p, opts.flagName, opts.envName, parseDefault(opts.defaultVal, base64.StdEncoding.DecodeString), opts.usage)
+
+	case "hex":
+		f.BytesHexVarE(p, opts.flagName, opts.envName, parseDefault(opts.defaultVal, hex.DecodeString), opts.usage)
+
+	default:
+		return fmt.Errorf("invalid bytes encoding: %q", opts.encoding)
+	}
+
+	return nil
+}
+
+// sliceBinder returns the binder which defines the slice flag
+// using the separator from the field options.
+func sliceBinder[T any](typeName string, parse func(string) (T, error), format func(T) string) typeBinder {
+	return func(f *FlagSet, ptr any, opts fieldOpts) error {
+		p, err := fieldPtr[[]T](ptr)
+		if err != nil {
+			return err
+		}
+
+		var def []T
+
+		if opts.defaultVal != "" {
+			value := newSliceValue(&def, nil, opts.sep, typeName, parse, format)
+			if err := value.Set(opts.defaultVal); err != nil {
+				def = nil
+			}
+		}
+
+		f.envVar(newSliceValue(p, def, opts.sep, typeName, parse, format), opts.flagName, opts.envName, opts.usage)
+
+		return nil
+	}
+}
+
+// mapBinder returns the binder which defines the map flag using
+// the separator and duplicate key policy from the field options.
+func mapBinder[T any](typeName string, parse func(string) (T, error), format func(T) string) typeBinder {
+	return func(f *FlagSet, ptr any, opts fieldOpts) error {
+		p, err := fieldPtr[map[string]T](ptr)
+		if err != nil {
+			return err
+		}
+
+		var def map[string]T
+
+		if opts.defaultVal != "" {
+			value := newMapValue(&def, nil, opts.sep, opts.duplicates, typeName, parse, format)
+			if err := value.Set(opts.defaultVal); err != nil {
+				def = nil
+			}
+		}
+
+		v := newMapValue(p, def, opts.sep, opts.duplicates, typeName, parse, format)
+		f.envVar(v, opts.flagName, opts.envName, opts.usage)
+
+		return nil
+	}
+}
+
+// fieldPtr converts the pointer to the struct field to the pointer of the type T.
+func fieldPtr[T any](ptr any) (*T, error) {
+	p, ok := ptr.(*T)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for %s field", reflect.TypeFor[T]())
+	}
+
+	return p, nil
+}
+
+// varE defines the flag of the generic value optionally bound to the environment variable.
+func varE[T any](f *FlagSet, v *genericValue[T], name, env string, def T, usage string) {
+	*v.p = def
+
+	f.envVar(v, name, env, usage)
+}
+
+// genericValue implements flag.Value for any type
+// using the given parse and format functions.
+type genericValue[T any] struct {
+	p        *T
+	typeName string
+	parse    func(string) (T, error)
+	format   func(T) string
+}
+
+func newGenericValue[T any](p *T, typeName string, parse func(string) (T, error), format func(T) string) *genericValue[T] {
+	return &genericValue[T]{p: p, typeName: typeName, parse: parse, format: format}
+}
+
+func (v *genericValue[T]) Set(s string) error {
+	parsed, err := v.parse(s)
+	if err != nil {
+		return err
+	}
+
+	*v.p = parsed
+
+	return nil
+}
+
+func (v *genericValue[T]) String() string {
+	// The flag package calls String on the zero value.
+	if v == nil || v.p == nil {
+		return ""
+	}
+
+	return v.format(*v.p)
+}
+
+func (v *genericValue[T]) Type() string { return v.typeName }
+
+// IsBoolFlag allows to set the flag of boolean kind without a value.
+func (*genericValue[T]) IsBoolFlag() bool { return reflect.TypeFor[T]().Kind() == reflect.Bool }
+
+// textValue implements flag.Value for types implementing
+// encoding.TextUnmarshaler. The value is formatted with
+// encoding.TextMarshaler when the type implements it.
+type textValue struct {
+	u encoding.TextUnmarshaler
+}
+
+func newTextValue(u encoding.TextUnmarshaler) *textValue {
+	return &textValue{u: u}
+}
+
+func (v *textValue) Set(s string) error {
+	return v.u.UnmarshalText([]byte(s))
+}
+
+func (v *textValue) String() string {
+	// The flag package calls String on the zero value.
+	if v == nil || v.u == nil {
+		return ""
+	}
+
+	if m, ok := v.u.(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return ""
+		}
+
+		return string(text)
+	}
+
+	return fmt.Sprint(reflect.ValueOf(v.u).Elem())
+}
+
+func (v *textValue) Type() string { return typeNameOf(reflect.TypeOf(v.u).Elem()) }
+
+// parseDefault converts the default value from the struct tag
+// or returns the zero value when it is empty or can't be parsed.
+func parseDefault[T any](s string, parse func(string) (T, error)) T {
+	var zero T
+
+	if s == "" {
+		return zero
+	}
+
+	parsed, err := parse(s)
+
+	return tern(err == nil, parsed, zero)
+}
+
+// typeNameOf returns the type name shown in the help output.
+func typeNameOf(t reflect.Type) string {
+	if t.Name() == "" {
+		return t.String()
+	}
+
+	return strings.ToLower(t.Name())
+}
+
+func formatAny[T any](v T) string { return fmt.Sprint(v) }
+
+func parseInt64(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) }
+
+func parseUint(s string) (uint, error) {
+	parsed, err := strconv.ParseUint(s, 10, strconv.IntSize)
+	return uint(parsed), err
+}
+
+func parseUint64(s string) (uint64, error) { return strconv.ParseUint(s, 10, 64) }
+
+func parseFloat64(s string) (float64, error) { return strconv.ParseFloat(s, 64) }
+
+// parseSigned parses the signed integer of the size of the type T.
+// Values which overflow the type are reported as errors.
+func parseSigned[T ~int8 | ~int16 | ~int32](s string) (T, error) {
+	parsed, err := strconv.ParseInt(s, 10, reflect.TypeFor[T]().Bits())
+	return T(parsed), err
+}
+
+func formatSigned[T ~int8 | ~int16 | ~int32](v T) string { return strconv.FormatInt(int64(v), 10) }
+
+// parseUnsigned parses the unsigned integer of the size of the type T.
+// Values which overflow the type are reported as errors.
+func parseUnsigned[T ~uint8 | ~uint16 | ~uint32](s string) (T, error) {
+	parsed, err := strconv.ParseUint(s, 10, reflect.TypeFor[T]().Bits())
+	return T(parsed), err
+}
+
+func formatUnsigned[T ~uint8 | ~uint16 | ~uint32](v T) string {
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+func parseFloat32(s string) (float32, error) {
+	parsed, err := strconv.ParseFloat(s, 32)
+	return float32(parsed), err
+}
+
+func formatFloat32(v float32) string { return strconv.FormatFloat(float64(v), 'g', -1, 32) }
+
+func parseTime(layout string) func(string) (time.Time, error) {
+	return func(s string) (time.Time, error) { return time.Parse(layout, s) }
+}
+
+func formatTime(layout string) func(time.Time) string {
+	return func(t time.Time) string { return tern(t.IsZero(), "", t.Format(layout)) }
+}
+
+func formatURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	return u.String()
+}
+
+func formatAddr(a netip.Addr) string { return tern(a.IsValid(), a.String(), "") }
+
+func formatAddrPort(a netip.AddrPort) string { return tern(a.IsValid(), a.String(), "") }
+
+func formatPrefix(p netip.Prefix) string { return tern(p.IsValid(), p.String(), "") }
+
+func formatRegexp(re *regexp.Regexp) string {
+	if re == nil {
+		return ""
+	}
+
+	return re.String()
+}
+
+// parseFileMode parses octal permission bits with an optional 0o prefix.
+func parseFileMode(s string) (os.FileMode, error) {
+	parsed, err := strconv.ParseUint(strings.TrimPrefix(s, "0o"), 8, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	if parsed > uint64(os.ModePerm) {
+		return 0, fmt.Errorf("invalid file mode %q: want permission bits", s)
+	}
+
+	return os.FileMode(parsed), nil
+}
+
+func formatFileMode(m os.FileMode) string { return fmt.Sprintf("%#o", uint32(m)) }
@@ -0,0 +1,143 @@
+package scotty
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testLevel int
+
+func parseTestLevel(s string) (testLevel, error) {
+	switch s {
+	case "debug":
+		return 1, nil
+	case "info":
+		return 2, nil
+	default:
+		return 0, errors.New("unknown level")
+	}
+}
+
+func formatTestLevel(l testLevel) string {
+	return tern(l == 1, "debug", tern(l == 2, "info", ""))
+}
+
+func TestVarE(t *testing.T) {
+	type tcase struct {
+		want    testLevel
+		env     string
+		args    []string
+		wantErr bool
+	}
+
+	tests := map[string]tcase{
+		"Flag":        {want: 1, args: []string{"-level", "debug"}},
+		"Default":     {want: 2},
+		"Env":         {want: 1, env: "debug"},
+		"BothSet":     {want: 2, env: "debug", args: []string{"-level=info"}},
+		"InvalidEnv":  {want: 2, env: "trace"},
+		"InvalidFlag": {args: []string{"-level", "trace"}, wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TEST_LEVEL", tc.env)
+
+			f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+			f.SetOutput(discard{})
+
+			var got testLevel
+
+			VarE(f, &got, "level", "TEST_LEVEL", 2, "", parseTestLevel)
+
+			err := f.Parse(tc.args)
+			if tc.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.want != got {
+				t.Errorf("want := %v, got := %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestVarE_Bool(t *testing.T) {
+	type toggle bool
+
+	f := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ContinueOnError)}
+
+	var got toggle
+
+	VarE(f, &got, "toggle", "", false, "", func(s string) (toggle, error) { return s == "true", nil })
+
+	if err := f.Parse([]string{"-toggle"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !got {
+		t.Error("expected toggle to be set without a value")
+	}
+}
+
+func TestRegisterType(t *testing.T) {
+	RegisterType(parseTestLevel, formatTestLevel)
+
+	type config struct {
+		Level  testLevel            `flag:"level" env:"TEST_LEVEL" default:"info" usage:"Log level"`
+		Levels []testLevel          `flag:"levels" default:"debug,info" usage:"Log levels"`
+		ByPkg  map[string]testLevel `flag:"by-pkg" usage:"Log levels by package"`
+	}
+
+	t.Setenv("TEST_LEVEL", "debug")
+
+	cfg := &config{}
+	cmd := &Command{Name: "test"}
+
+	if err := cmd.BindConfig(cfg); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	if err := cmd.Flags().Parse([]string{"-by-pkg", "http=info"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if cfg.Level != 1 {
+		t.Errorf("Level = %v, want %v", cfg.Level, 1)
+	}
+
+	if want := []testLevel{1, 2}; !reflect.DeepEqual(cfg.Levels, want) {
+		t.Errorf("Levels = %v, want %v", cfg.Levels, want)
+	}
+
+	if want := map[string]testLevel{"http": 2}; !reflect.DeepEqual(cfg.ByPkg, want) {
+		t.Errorf("ByPkg = %v, want %v", cfg.ByPkg, want)
+	}
+
+	var b strings.Builder
+
+	if err := cmd.renderHelp(&b, terminal{width: 120}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"-level testlevel", "-levels []testlevel", "-by-pkg map[string]testlevel"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+		}
+	}
+
+	if got := cmd.Flags().Lookup("level").DefValue; got != "info" {
+		t.Errorf("DefValue = %q, want %q", got, "info")
+	}
+}
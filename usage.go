@@ -3,107 +3,700 @@ package scotty
 import (
 	"flag"
 	"fmt"
-	"reflect"
+	"io"
+	"maps"
+	"os"
 	"slices"
+	"strconv"
 	"strings"
+	"text/template"
 	"unicode/utf8"
 )
 
+// DefaultHelpTemplate is the text/template used to render the help output
+// of commands which don't override it via Command.HelpTemplate.
+// The template is executed with HelpData.
+const DefaultHelpTemplate = `{{.Root}}{{with .RootShort}} - {{.}}{{end}}
+
+{{heading "Usage:"}}
+  {{.Path}} {{if .HasFlags}}<flags> {{end}}{{if .Commands}}[command]{{else}}{{.ArgsUsage}}{{end}}
+{{- range .CommandGroups}}
+
+{{heading (print .Title ":")}}
+{{- range .Commands}}
+  {{rpad .Name $.CommandsWidth}}  {{wrap (add $.CommandsWidth 4) (annotate .Short .Notes)}}
+{{- end}}
+{{- end}}
+{{- range .FlagGroups}}
+
+{{heading (print .Title ":")}}
+{{- range .Flags}}
+  {{rpad (print (flagName .Flag) " " .Type) (add $.FlagsWidth 1)}}  {{wrap (add $.FlagsWidth 5) (annotate .Usage .Notes)}}
+{{- end}}
+{{- end}}
+{{- with .Example}}
+
+{{heading "Examples:"}}
+{{.}}
+{{- end}}
+
+Use '{{.Path}} -help' for more information about a command.
+
+`
+
+// Terminal related defaults of the help output.
+const (
+	// defaultHelpWidth is used when the width of the terminal can't be detected.
+	defaultHelpWidth = 80
+
+	// minWrapWidth is the minimal width of the text wrapped by the wrap function.
+	minWrapWidth = 20
+)
+
+// ANSI escape sequences used to style the help output.
+const (
+	ansiBold  = "\x1b[1m"
+	ansiCyan  = "\x1b[36m"
+	ansiReset = "\x1b[0m"
+)
+
+// HelpData is the data model passed to the help template.
+type HelpData struct {
+	// Root holds the name of the root command.
+	Root string
+
+	// RootShort holds the short description of the root command.
+	RootShort string
+
+	// Name holds the name of the command.
+	Name string
+
+	// Path holds the chain of command names from the root
+	// to the command separated by spaces, e.g. "app db migrate".
+	Path string
+
+	// Short holds the short description of the command.
+	Short string
+
+	// Long holds the long description of the command.
+	Long string
+
+	// ArgsUsage holds the description of the positional arguments.
+	ArgsUsage string
+
+	// Example holds the examples of the command usage.
+	Example string
+
+	// HasFlags reports whether the command has any flags.
+	HasFlags bool
+
+	// Commands holds the subcommands sorted by name.
+	Commands []HelpCommand
+
+	// CommandGroups holds the subcommands split into help sections.
+	CommandGroups []HelpCommandGroup
+
+	// CommandsWidth holds the length of the longest subcommand name.
+	CommandsWidth int
+
+	// FlagGroups holds the flags split into help sections.
+	FlagGroups []HelpFlagGroup
+
+	// FlagsWidth holds the length of the longest "name type" flag pair.
+	FlagsWidth int
+
+	// Width holds the width of the output in columns.
+	Width int
+
+	// Color reports whether the output is styled with ANSI escape sequences.
+	Color bool
+}
+
+// HelpCommand describes a subcommand in the help output.
+type HelpCommand struct {
+	// Name holds the name of the subcommand.
+	Name string
+
+	// Short holds the short description of the subcommand.
+	Short string
+
+	// Deprecated holds the deprecation message of the subcommand.
+	Deprecated string
+}
+
+// Notes returns the notes to show next to the short description of the subcommand.
+func (c HelpCommand) Notes() []string {
+	var notes []string
+
+	if c.Deprecated != "" {
+		notes = append(notes, "deprecated: "+c.Deprecated)
+	}
+
+	return notes
+}
+
+// HelpCommandGroup describes a section of subcommands in the help output.
+type HelpCommandGroup struct {
+	// ID holds the ID of the group. It is empty for the default section.
+	ID string
+
+	// Title holds the section title, e.g. "Management Commands".
+	Title string
+
+	// Commands holds the subcommands of the section sorted by name.
+	Commands []HelpCommand
+}
+
+// HelpFlagGroup describes a section of flags in the help output.
+type HelpFlagGroup struct {
+	// Title holds the section title, e.g. "Flags" or "Global Flags".
+	Title string
+
+	// Group holds the name of the flag group. It is empty
+	// for the sections of ungrouped and global flags.
+	Group string
+
+	// Global reports whether the section holds
+	// persistent flags inherited from ancestors.
+	Global bool
+
+	// Flags holds the flags of the section.
+	Flags []HelpFlag
+}
+
+// HelpFlag describes a flag in the help output.
+type HelpFlag struct {
+	// Name holds the name of the flag without the leading dash.
+	Name string
+
+	// Type holds the type name or placeholder of the flag value.
+	// It is empty for counters which are given without a value.
+	Type string
+
+	// Usage holds the usage string of the flag.
+	Usage string
+
+	// Default holds the default value of the flag as text,
+	// masked for secret flags. Zero values are not shown in the notes.
+	Default string
+
+	// Optional reports whether the flag value stays unset unless the flag is provided.
+	Optional bool
+
+	// Enum holds the allowed values of the flag.
+	Enum []string
+
+	// Rules holds the descriptions of the validation rules of the flag value, e.g. "min: 1".
+	Rules []string
+
+	// Negatable reports whether the boolean flag has the negating -no- flag.
+	Negatable bool
+
+	// Repeatable reports whether the flag is a counter
+	// which is incremented by each occurrence.
+	Repeatable bool
+
+	// Constraints holds the descriptions of the relationship constraints
+	// with other flags, e.g. "conflicts with -url".
+	Constraints []string
+
+	// Deprecated holds the deprecation message of the flag.
+	Deprecated string
+
+	// Env holds the name of the environment variable bound to the flag.
+	Env string
+}
+
+// Flag returns the flag as shown in the help output: -name,
+// or -[no-]name for negatable boolean flags.
+func (f HelpFlag) Flag() string {
+	return tern(f.Negatable, "-[no-]", "-") + f.Name
+}
+
+// Notes returns the notes to show next to the usage string of the flag.
+func (f HelpFlag) Notes() []string {
+	var notes []string
+
+	if f.Optional {
+		notes = append(notes, "optional")
+	}
+
+	if f.Repeatable {
+		notes = append(notes, "repeatable")
+	}
+
+	if len(f.Enum) > 0 {
+		notes = append(notes, "one of: "+strings.Join(f.Enum, ", "))
+	}
+
+	if !f.Optional && !isZeroDefault(f.Default) {
+		notes = append(notes, "default: "+f.Default)
+	}
+
+	notes = append(notes, f.Rules...)
+
+	notes = append(notes, f.Constraints...)
+
+	if f.Env != "" {
+		notes = append(notes, "env: "+f.Env)
+	}
+
+	if f.Deprecated != "" {
+		notes = append(notes, "deprecated: "+f.Deprecated)
+	}
+
+	return notes
+}
+
+// isZeroDefault reports whether the default value of the flag is the zero
+// value of its type, which is not worth showing in the help output.
+func isZeroDefault(value string) bool {
+	switch value {
+	case "", "false", "0", "0s", "0B":
+		return true
+
+	default:
+		return false
+	}
+}
+
 func (c *Command) usage() {
 	// Define the single strings.Builder
 	// for the output of the command usage.
 	var b strings.Builder
 
+	term := detectTerminal(c.Flags().Output())
+
+	if err := c.renderHelp(&b, term); err != nil {
+		b.Reset()
+		fmt.Fprintf(&b, "help template: %v\n\n", err)
+
+		//nolint:errcheck // The default template is known to be valid.
+		executeHelpTemplate(&b, DefaultHelpTemplate, c.helpFuncs(term), c.helpData(term))
+	}
+
+	if _, err := fmt.Fprint(c.Flags().Output(), b.String()); err != nil {
+		fmt.Print(b.String())
+	}
+}
+
+// renderHelp renders the help output of the command into w using
+// the nearest help template and functions defined in the command chain.
+func (c *Command) renderHelp(w io.Writer, term terminal) error {
+	return executeHelpTemplate(w, c.helpTemplate(), c.helpFuncs(term), c.helpData(term))
+}
+
+// helpTemplate returns the help template of the nearest command in
+// the chain from the command to the root which overrides it.
+func (c *Command) helpTemplate() string {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.HelpTemplate != "" {
+			return cmd.HelpTemplate
+		}
+	}
+
+	return DefaultHelpTemplate
+}
+
+// helpFuncs returns the built-in help template functions merged with
+// the functions defined in the command chain from the root to the
+// command, so the functions of descendants take precedence.
+func (c *Command) helpFuncs(term terminal) template.FuncMap {
+	funcs := template.FuncMap{
+		"rpad":     rpad,
+		"join":     strings.Join,
+		"add":      func(a, b int) int { return a + b },
+		"wrap":     func(indent int, s string) string { return wrap(s, indent, term.width) },
+		"heading":  func(s string) string { return term.style(ansiBold, s) },
+		"flagName": func(s string) string { return term.style(ansiCyan, s) },
+		"annotate": annotate,
+	}
+
+	chain := make([]*Command, 0, 1)
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		chain = append(chain, cmd)
+	}
+
+	for _, cmd := range slices.Backward(chain) {
+		maps.Copy(funcs, cmd.HelpFuncs)
+	}
+
+	return funcs
+}
+
+// helpData collects the data model of the help output.
+func (c *Command) helpData(term terminal) HelpData {
 	root := c.TraverseToRoot()
-	if root.Short != "" {
-		fmt.Fprintf(&b, "%s - %s\n\n", root.Name, root.Short)
-	} else {
-		fmt.Fprintf(&b, "%s\n\n", root.Name)
+	flags := c.Flags()
+
+	data := HelpData{
+		Root:      root.Name,
+		RootShort: root.Short,
+		Name:      c.Name,
+		Path:      commandsChain(c),
+		Short:     c.Short,
+		Long:      c.Long,
+		ArgsUsage: tern(c.ArgsUsage != "", c.ArgsUsage, "[arguments...]"),
+		Example:   c.Example,
+		HasFlags:  hasFlags(flags),
+		Width:     term.width,
+		Color:     term.color,
 	}
 
-	b.WriteString("Usage:\n")
-	printCommandCallUsage(&b, c)
+	for _, cmd := range sortedSubcommands(c.subcommands) {
+		if cmd.Hidden {
+			continue
+		}
+
+		data.Commands = append(data.Commands, HelpCommand{
+			Name:       cmd.Name,
+			Short:      cmd.Short,
+			Deprecated: cmd.Deprecated,
+		})
+
+		data.CommandsWidth = max(data.CommandsWidth, utf8.RuneCountInString(cmd.Name))
+	}
+
+	data.CommandGroups = c.commandGroups(data.Commands)
+
+	for _, section := range flagSections(flags) {
+		group := HelpFlagGroup{
+			Title:  section.title,
+			Group:  section.group,
+			Global: section.global,
+			Flags:  make([]HelpFlag, 0, len(section.flags)),
+		}
+
+		for _, f := range section.flags {
+			fType, usage := flagTypeName(flags, f)
+
+			m := flags.lookupMeta(f.Name)
 
-	printSubcommands(&b, c.subcommands)
-	printFlags(&b, c.Flags())
-	printHelpSuggestion(&b, c)
+			_, repeatable := f.Value.(*countValue)
 
-	if _, err := fmt.Fprintln(c.Flags().Output(), b.String()); err != nil {
-		fmt.Println(b.String())
+			hf := HelpFlag{
+				Name:        f.Name,
+				Type:        fType,
+				Usage:       usage,
+				Default:     f.DefValue,
+				Optional:    m.optional,
+				Enum:        m.enum,
+				Rules:       m.rules,
+				Negatable:   m.negation != "",
+				Repeatable:  repeatable,
+				Constraints: flags.constraintNotes(f.Name),
+				Deprecated:  m.deprecated,
+				Env:         flags.envName(f.Name),
+			}
+
+			group.Flags = append(group.Flags, hf)
+
+			// The width doesn't include the leading dash.
+			data.FlagsWidth = max(data.FlagsWidth, utf8.RuneCountInString(hf.Flag()+" "+fType)-1)
+		}
+
+		data.FlagGroups = append(data.FlagGroups, group)
 	}
+
+	return data
 }
 
-func printCommandCallUsage(b *strings.Builder, c *Command) {
-	fmt.Fprintf(b, "  %s ", commandsChain(c))
+// commandGroups splits the help descriptions of subcommands into sections.
+// The sections of declared groups go first, in the order of declaration or
+// sorted by title, followed by the default section with the rest of commands.
+// Sections without commands are omitted.
+func (c *Command) commandGroups(commands []HelpCommand) []HelpCommandGroup {
+	groups := make([]HelpCommandGroup, 0, len(c.groups)+1)
+	indexes := make(map[string]int, len(c.groups))
+
+	for _, group := range c.groups {
+		indexes[group.id] = len(groups)
+		groups = append(groups, HelpCommandGroup{ID: group.id, Title: group.title})
+	}
 
-	if hasFlags(c.Flags()) {
-		b.WriteString("<flags> ")
+	if c.SortGroups {
+		slices.SortStableFunc(groups, func(a, b HelpCommandGroup) int {
+			return strings.Compare(a.Title, b.Title)
+		})
+
+		for i, group := range groups {
+			indexes[group.ID] = i
+		}
 	}
 
-	if len(c.subcommands) > 0 {
-		b.WriteString("[command]\n")
-	} else {
-		b.WriteString("[arguments...]\n")
+	var ungrouped []HelpCommand
+
+	for _, cmd := range commands {
+		i, ok := indexes[c.subcommands[cmd.Name].Group]
+		if !ok {
+			ungrouped = append(ungrouped, cmd)
+			continue
+		}
+
+		groups[i].Commands = append(groups[i].Commands, cmd)
 	}
+
+	groups = slices.DeleteFunc(groups, func(group HelpCommandGroup) bool {
+		return len(group.Commands) == 0
+	})
+
+	if len(ungrouped) > 0 {
+		groups = append(groups, HelpCommandGroup{
+			Title:    tern(len(groups) > 0, "Additional Commands", "Available Commands"),
+			Commands: ungrouped,
+		})
+	}
+
+	return groups
 }
 
-func printSubcommands(b *strings.Builder, subcommands map[string]*Command) {
-	if b == nil || len(subcommands) == 0 {
-		return
+// executeHelpTemplate parses the help template text and executes it with data.
+func executeHelpTemplate(w io.Writer, text string, funcs template.FuncMap, data HelpData) error {
+	tmpl, err := template.New("help").Funcs(funcs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("parse: %w", err)
 	}
 
-	b.WriteString("\nAvailable Commands:\n")
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("execute: %w", err)
+	}
+
+	return nil
+}
+
+// annotate appends the notes to s in parentheses separated by semicolons.
+func annotate(s string, notes []string) string {
+	if len(notes) == 0 {
+		return s
+	}
+
+	return strings.TrimSpace(s + " (" + strings.Join(notes, "; ") + ")")
+}
+
+// rpad pads s with spaces on the right up to the given width
+// in columns. ANSI escape sequences don't count to the width.
+func rpad(s string, width int) string {
+	if n := width - visibleWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+
+	return s
+}
+
+// wrap wraps s into lines which fit into the given width, assuming the first
+// line starts at the indent column. The rest of lines are indented by indent
+// spaces, which gives a hanging indent. Existing line breaks are preserved.
+func wrap(s string, indent, width int) string {
+	limit := max(width-indent, minWrapWidth)
+	pad := strings.Repeat(" ", indent)
+
+	var b strings.Builder
+
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			b.WriteString("\n" + pad)
+		}
+
+		col := 0
+
+		for _, word := range strings.Fields(line) {
+			wordWidth := visibleWidth(word)
+
+			switch {
+			case col == 0:
+			case col+1+wordWidth > limit:
+				b.WriteString("\n" + pad)
+				col = 0
+			default:
+				b.WriteString(" ")
+				col++
+			}
 
-	sorted := sortedSubcommands(subcommands)
-	longest := 0
+			b.WriteString(word)
+			col += wordWidth
+		}
+	}
 
-	for _, c := range sorted {
-		nameLen := utf8.RuneCountInString(c.Name)
+	return b.String()
+}
 
-		if longest < nameLen {
-			longest = nameLen
+// visibleWidth returns the number of columns s takes
+// in a terminal, skipping ANSI escape sequences.
+func visibleWidth(s string) int {
+	width := 0
+	escape := false
+
+	for _, r := range s {
+		switch {
+		case escape:
+			// An escape sequence ends with a letter.
+			escape = !(r >= '@' && r <= '~' && r != '[')
+		case r == '\x1b':
+			escape = true
+		default:
+			width++
 		}
 	}
 
-	for _, c := range sorted {
-		fmt.Fprintf(b, "  %s %s\n", c.Name+indent(c.Name, longest, 1), c.Short)
+	return width
+}
+
+// terminal describes the capabilities of the help output destination.
+type terminal struct {
+	// width holds the width of the output in columns.
+	width int
+
+	// color reports whether the output can be styled.
+	color bool
+}
+
+// detectTerminal detects the width and the color support of w.
+// The width is taken from the terminal, then from the COLUMNS environment
+// variable and at last defaults to 80. The output is styled only when w
+// is a terminal, the NO_COLOR environment variable is empty and TERM
+// is not "dumb".
+func detectTerminal(w io.Writer) terminal {
+	var term terminal
+
+	tty, detected := false, false
+
+	if file, ok := w.(*os.File); ok && file != nil && isTerminal(file.Fd()) {
+		tty = true
+		term.width, detected = terminalWidth(file.Fd())
+	}
+
+	if !detected {
+		term.width = defaultHelpWidth
+
+		if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+			term.width = columns
+		}
 	}
+
+	term.color = tty && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+
+	return term
 }
 
-func printFlags(b *strings.Builder, flags *FlagSet) {
-	if b == nil || flags == nil {
-		return
+// style wraps s into the ANSI escape sequence
+// if the output supports styling.
+func (t terminal) style(sequence, s string) string {
+	if !t.color || s == "" {
+		return s
 	}
 
-	flagCount := 0
-	longest := 0
+	return sequence + s + ansiReset
+}
+
+// flagSection represents a titled list of flags in the help output.
+type flagSection struct {
+	title  string
+	group  string
+	global bool
+	flags  []*flag.Flag
+}
+
+// flagSections splits flags into the help output sections: the local flags
+// without a group go first, then a section per each group of local flags in
+// the order of groups declaration and at last the inherited persistent flags.
+// Hidden flags and sections without flags are omitted.
+func flagSections(flags *FlagSet) []flagSection {
+	var local, global []*flag.Flag
+
+	grouped := make(map[string][]*flag.Flag, len(flags.groups))
 
 	flags.VisitAll(func(f *flag.Flag) {
-		flagCount++
+		m := flags.lookupMeta(f.Name)
+
+		switch {
+		case m.hidden:
 
-		fType := reflect.TypeOf(f.Value).Elem().Kind().String()
-		nameLen := utf8.RuneCountInString(f.Name + " " + fType)
+		case m.inherited:
+			global = append(global, f)
 
-		if longest < nameLen {
-			longest = nameLen
+		case m.group != "":
+			grouped[m.group] = append(grouped[m.group], f)
+
+		default:
+			local = append(local, f)
 		}
 	})
 
-	if flagCount > 0 {
-		b.WriteString("\nFlags:\n")
-		flags.VisitAll(func(f *flag.Flag) {
-			fType := reflect.TypeOf(f.Value).Elem().Kind().String()
+	sections := make([]flagSection, 0, len(flags.groups)+2)
 
-			fmt.Fprintf(b, "  -%s %s\n",
-				f.Name+" "+fType+indent(f.Name+" "+fType, longest, 1),
-				f.Usage,
-			)
-		})
+	if len(local) > 0 {
+		sections = append(sections, flagSection{title: "Flags", flags: local})
 	}
+
+	for _, group := range flags.groups {
+		if len(grouped[group]) > 0 {
+			sections = append(sections, flagSection{
+				title: group + " Flags",
+				group: group,
+				flags: grouped[group],
+			})
+		}
+	}
+
+	if len(global) > 0 {
+		sections = append(sections, flagSection{title: "Global Flags", global: true, flags: global})
+	}
+
+	return sections
 }
 
-func printHelpSuggestion(b *strings.Builder, c *Command) {
-	fmt.Fprintf(b, "\nUse '%s -help' for more information about a command.\n", commandsChain(c))
+// Typer is implemented by flag.Value types which want
+// to control the type name shown in the help output.
+type Typer interface {
+	// Type returns the type name of the value, e.g. "duration" or "[]string".
+	Type() string
+}
+
+// flagTypeName returns the type name or placeholder to show next to the flag
+// in the help output together with the usage string to show for it.
+// A backquoted name in the usage string has the highest priority, as with
+// flag.UnquoteUsage, then comes the placeholder set via struct tag, then the
+// name reported by the Typer interface and at last the standard type name.
+// Counters have no type name, since they are given without a value.
+func flagTypeName(flags *FlagSet, f *flag.Flag) (string, string) {
+	placeholder, usage := flagPlaceholder(flags, f)
+	if placeholder != "" {
+		return placeholder, usage
+	}
+
+	if _, ok := f.Value.(*countValue); ok {
+		return "", usage
+	}
+
+	return flagValueType(f), usage
+}
+
+// flagPlaceholder returns the placeholder of the flag value set by a backquoted
+// name in the usage string or by struct tag, together with the usage string
+// without backquotes. The placeholder is empty if none is set.
+func flagPlaceholder(flags *FlagSet, f *flag.Flag) (string, string) {
+	name, usage := flag.UnquoteUsage(f)
+	if strings.Contains(f.Usage, "`") {
+		return name, usage
+	}
+
+	return flags.lookupMeta(f.Name).placeholder, usage
+}
+
+// flagValueType returns the type name of the flag value reported by
+// the Typer interface or the standard type name of the flag package.
+func flagValueType(f *flag.Flag) string {
+	if typer, ok := f.Value.(Typer); ok {
+		return typer.Type()
+	}
+
+	// The flag.UnquoteUsage returns an empty name for boolean flags.
+	name, _ := flag.UnquoteUsage(&flag.Flag{Value: f.Value})
+	if name == "" {
+		return "bool"
+	}
+
+	return name
 }
 
 func sortedSubcommands(subcommands map[string]*Command) []*Command {
@@ -154,19 +747,10 @@ func walkCommandsChain(c *Command, f func(name string)) {
 	walkCommandsChain(c.parent, f)
 }
 
-func indent(name string, longest, offset int) string {
-	whitespace := ""
-	for i := utf8.RuneCountInString(name); i < longest+offset; i++ {
-		whitespace += " "
-	}
-
-	return whitespace
-}
-
 func hasFlags(flags *FlagSet) bool {
 	has := false
 
-	flags.VisitAll(func(_ *flag.Flag) { has = true })
+	flags.VisitAll(func(f *flag.Flag) { has = has || !flags.isHidden(f.Name) })
 
 	return has
 }
@@ -2,7 +2,11 @@ package scotty
 
 import (
 	"flag"
+	"net"
+	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 func Test_hasFlags(t *testing.T) {
@@ -39,3 +43,490 @@ func Test_hasFlags(t *testing.T) {
 		})
 	}
 }
+
+type testTyperValue []string
+
+func (v *testTyperValue) String() string { return strings.Join(*v, ",") }
+
+func (v *testTyperValue) Set(s string) error {
+	*v = append(*v, s)
+	return nil
+}
+
+func (*testTyperValue) Type() string { return "[]string" }
+
+type testPlainValue struct{ value string }
+
+func (v *testPlainValue) String() string { return v.value }
+
+func (v *testPlainValue) Set(s string) error {
+	v.value = s
+	return nil
+}
+
+func Test_flagTypeName(t *testing.T) {
+	type tcase struct {
+		setup     func(f *FlagSet)
+		wantType  string
+		wantUsage string
+	}
+
+	tests := map[string]tcase{
+		"String": {
+			setup:     func(f *FlagSet) { f.String("f", "", "set value") },
+			wantType:  "string",
+			wantUsage: "set value",
+		},
+		"Bool": {
+			setup:     func(f *FlagSet) { f.Bool("f", false, "enable") },
+			wantType:  "bool",
+			wantUsage: "enable",
+		},
+		"Duration": {
+			setup:     func(f *FlagSet) { f.Duration("f", time.Second, "timeout") },
+			wantType:  "duration",
+			wantUsage: "timeout",
+		},
+		"Backquoted": {
+			setup:     func(f *FlagSet) { f.String("f", "", "listen on `ADDR`") },
+			wantType:  "ADDR",
+			wantUsage: "listen on ADDR",
+		},
+		"Placeholder": {
+			setup: func(f *FlagSet) {
+				f.String("f", "", "listen address")
+				f.metaFor("f").placeholder = "ADDR"
+			},
+			wantType:  "ADDR",
+			wantUsage: "listen address",
+		},
+		"Typer": {
+			setup:     func(f *FlagSet) { f.Var(&testTyperValue{}, "f", "tags") },
+			wantType:  "[]string",
+			wantUsage: "tags",
+		},
+		"Custom value": {
+			setup:     func(f *FlagSet) { f.Var(&testPlainValue{}, "f", "custom") },
+			wantType:  "value",
+			wantUsage: "custom",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			set := &FlagSet{FlagSet: flag.NewFlagSet("test", flag.ExitOnError)}
+			tc.setup(set)
+
+			gotType, gotUsage := flagTypeName(set, set.Lookup("f"))
+			if gotType != tc.wantType {
+				t.Errorf("type: expected := %q, got := %q", tc.wantType, gotType)
+			}
+
+			if gotUsage != tc.wantUsage {
+				t.Errorf("usage: expected := %q, got := %q", tc.wantUsage, gotUsage)
+			}
+		})
+	}
+}
+
+func TestCommand_renderHelp(t *testing.T) {
+	type tcase struct {
+		cmd  func() *Command
+		want string
+	}
+
+	tests := map[string]tcase{
+		"Default": {
+			cmd: func() *Command {
+				root := &Command{Name: "app", Short: "The app"}
+				root.AddSubcommands(
+					&Command{Name: "serve", Short: "Start the server"},
+					&Command{Name: "db", Short: "Manage the database"},
+				)
+				root.Flags().Duration("timeout", time.Second, "request timeout")
+				root.Flags().String("addr", "", "listen on `ADDR`")
+
+				return root
+			},
+			want: "app - The app\n\n" +
+				"Usage:\n" +
+				"  app <flags> [command]\n\n" +
+				"Available Commands:\n" +
+				"  db     Manage the database\n" +
+				"  serve  Start the server\n\n" +
+				"Flags:\n" +
+				"  -addr ADDR         listen on ADDR\n" +
+				"  -timeout duration  request timeout (default: 1s)\n\n" +
+				"Use 'app -help' for more information about a command.\n\n",
+		},
+
+		"Sections": {
+			cmd: func() *Command {
+				root := &Command{
+					Name: "root",
+					SetPersistentFlags: func(flags *FlagSet) {
+						flags.Bool("verbose", false, "verbose output")
+					},
+				}
+
+				sub := &Command{
+					Name:      "sub",
+					ArgsUsage: "<file>",
+					Example:   "  root sub -port 80 file.txt",
+					SetFlags: func(flags *FlagSet) {
+						flags.String("db-host", "", "database host")
+						flags.Int("port", 0, "server port")
+						flags.SetGroup("Database", "db-host")
+					},
+				}
+
+				root.AddSubcommands(sub)
+
+				return sub
+			},
+			want: "root\n\n" +
+				"Usage:\n" +
+				"  root sub <flags> <file>\n\n" +
+				"Flags:\n" +
+				"  -port int        server port\n\n" +
+				"Database Flags:\n" +
+				"  -db-host string  database host\n\n" +
+				"Global Flags:\n" +
+				"  -verbose bool    verbose output\n\n" +
+				"Examples:\n" +
+				"  root sub -port 80 file.txt\n\n" +
+				"Use 'root sub -help' for more information about a command.\n\n",
+		},
+
+		"Persistent flags of the defining command": {
+			cmd: func() *Command {
+				root := &Command{
+					Name: "root",
+					SetPersistentFlags: func(flags *FlagSet) {
+						flags.Bool("verbose", false, "verbose output")
+					},
+				}
+
+				root.AddSubcommands(&Command{Name: "sub"})
+
+				return root
+			},
+			want: "root\n\n" +
+				"Usage:\n" +
+				"  root <flags> [command]\n\n" +
+				"Available Commands:\n" +
+				"  sub  \n\n" +
+				"Flags:\n" +
+				"  -verbose bool  verbose output\n\n" +
+				"Use 'root -help' for more information about a command.\n\n",
+		},
+
+		"Tree template and funcs": {
+			cmd: func() *Command {
+				root := &Command{
+					Name:         "app",
+					HelpTemplate: `{{shout .Path}}{{range .FlagGroups}}|{{.Title}}{{range .Flags}}:{{.Name}}={{.Default}}{{end}}{{end}}`,
+					HelpFuncs:    map[string]any{"shout": strings.ToUpper},
+				}
+
+				sub := &Command{
+					Name:      "sub",
+					HelpFuncs: map[string]any{"shout": func(s string) string { return s + "!" }},
+				}
+				sub.Flags().Int("port", 8080, "")
+
+				root.AddSubcommands(sub)
+
+				return sub
+			},
+			want: "app sub!|Flags:port=8080",
+		},
+
+		"Invalid template": {
+			cmd: func() *Command {
+				return &Command{Name: "app", HelpTemplate: "{{.Unknown}}"}
+			},
+			want: "help template: execute: template: help:1:2: executing \"help\" at <.Unknown>: " +
+				"can't evaluate field Unknown in type scotty.HelpData\n\n" +
+				"app\n\n" +
+				"Usage:\n" +
+				"  app [arguments...]\n\n" +
+				"Use 'app -help' for more information about a command.\n\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b strings.Builder
+
+			cmd := tc.cmd()
+			cmd.Flags().SetOutput(&b)
+			cmd.Flags().Usage()
+
+			if got := b.String(); got != tc.want {
+				t.Errorf("Expected := %q, got := %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func Test_wrap(t *testing.T) {
+	type tcase struct {
+		s      string
+		indent int
+		width  int
+		want   string
+	}
+
+	tests := map[string]tcase{
+		"Fits": {
+			s:      "short text",
+			indent: 4,
+			width:  80,
+			want:   "short text",
+		},
+		"Hanging indent": {
+			s:      "the quick brown fox jumps over the lazy dog",
+			indent: 4,
+			width:  24,
+			want:   "the quick brown fox\n    jumps over the lazy\n    dog",
+		},
+		"Long word": {
+			s:      "a verylongwordwhichdoesnotfit b",
+			indent: 0,
+			width:  20,
+			want:   "a\nverylongwordwhichdoesnotfit\nb",
+		},
+		"Line breaks": {
+			s:      "first\nsecond",
+			indent: 2,
+			width:  80,
+			want:   "first\n  second",
+		},
+		"Minimal width": {
+			s:      "one two three four five six",
+			indent: 70,
+			width:  80,
+			want:   "one two three four\n" + strings.Repeat(" ", 70) + "five six",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := wrap(tc.s, tc.indent, tc.width); got != tc.want {
+				t.Errorf("Expected := %q, got := %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func Test_rpad(t *testing.T) {
+	if got := rpad("ab", 4); got != "ab  " {
+		t.Errorf("Expected := %q, got := %q", "ab  ", got)
+	}
+
+	if got := rpad(ansiCyan+"ab"+ansiReset, 4); got != ansiCyan+"ab"+ansiReset+"  " {
+		t.Errorf("Expected := %q, got := %q", ansiCyan+"ab"+ansiReset+"  ", got)
+	}
+
+	if got := rpad("abcdef", 4); got != "abcdef" {
+		t.Errorf("Expected := %q, got := %q", "abcdef", got)
+	}
+}
+
+func Test_detectTerminal(t *testing.T) {
+	t.Run("Not a file", func(t *testing.T) {
+		t.Setenv("COLUMNS", "")
+
+		want := terminal{width: defaultHelpWidth}
+		if got := detectTerminal(&strings.Builder{}); got != want {
+			t.Errorf("Expected := %+v, got := %+v", want, got)
+		}
+	})
+
+	t.Run("COLUMNS", func(t *testing.T) {
+		t.Setenv("COLUMNS", "120")
+
+		want := terminal{width: 120}
+		if got := detectTerminal(&strings.Builder{}); got != want {
+			t.Errorf("Expected := %+v, got := %+v", want, got)
+		}
+	})
+
+	t.Run("Not a terminal", func(t *testing.T) {
+		t.Setenv("COLUMNS", "")
+
+		file, err := os.CreateTemp(t.TempDir(), "help")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() { file.Close() })
+
+		want := terminal{width: defaultHelpWidth}
+		if got := detectTerminal(file); got != want {
+			t.Errorf("Expected := %+v, got := %+v", want, got)
+		}
+	})
+}
+
+func TestCommand_renderHelp_Terminal(t *testing.T) {
+	cmd := &Command{Name: "app"}
+	cmd.Flags().String("addr", "", "address the server listens on for incoming connections")
+
+	t.Run("Wrap", func(t *testing.T) {
+		var b strings.Builder
+
+		if err := cmd.renderHelp(&b, terminal{width: 40}); err != nil {
+			t.Fatal(err)
+		}
+
+		want := "\nFlags:\n" +
+			"  -addr string  address the server\n" +
+			"                listens on for incoming\n" +
+			"                connections\n"
+
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+		}
+	})
+
+	t.Run("Color", func(t *testing.T) {
+		var b strings.Builder
+
+		if err := cmd.renderHelp(&b, terminal{width: 80, color: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		want := "\n" + ansiBold + "Flags:" + ansiReset + "\n" +
+			"  " + ansiCyan + "-addr" + ansiReset + " string  address the server listens on for incoming connections\n"
+
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+		}
+	})
+}
+
+func TestCommand_renderHelp_HiddenDeprecated(t *testing.T) {
+	root := &Command{Name: "app"}
+	root.AddSubcommands(
+		&Command{Name: "serve", Short: "Start the server"},
+		&Command{Name: "start", Short: "Start the server", Deprecated: "use serve"},
+		&Command{Name: "debug", Short: "Debug internals", Hidden: true},
+	)
+
+	root.Flags().String("addr", "", "listen address")
+	root.Flags().String("listen", "", "listen address")
+	root.Flags().Bool("trace", false, "trace internals")
+	root.Flags().MarkDeprecated("listen", "use -addr")
+	root.Flags().MarkHidden("trace")
+
+	var b strings.Builder
+
+	if err := root.renderHelp(&b, terminal{width: 120}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Available Commands:\n" +
+		"  serve  Start the server\n" +
+		"  start  Start the server (deprecated: use serve)\n\n" +
+		"Flags:\n" +
+		"  -addr string    listen address\n" +
+		"  -listen string  listen address (deprecated: use -addr)\n\n"
+
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+	}
+}
+
+func TestCommand_renderHelp_Defaults(t *testing.T) {
+	type config struct {
+		IP      net.IP        `flag:"ip" default:"127.0.0.1" usage:"IP"`
+		Since   time.Time     `flag:"since" usage:"Since"`
+		Retries int           `flag:"retries" default:"3" usage:"Retries"`
+		Timeout time.Duration `flag:"timeout" usage:"Timeout"`
+		Debug   bool          `flag:"debug" usage:"Debug mode"`
+		Name    *string       `flag:"name" usage:"Name"`
+	}
+
+	cmd := &Command{Name: "app"}
+
+	if err := cmd.BindConfig(&config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+
+	if err := cmd.renderHelp(&b, terminal{width: 120}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Flags:\n" +
+		"  -debug bool        Debug mode\n" +
+		"  -ip ip             IP (default: 127.0.0.1)\n" +
+		"  -name string       Name (optional)\n" +
+		"  -retries int       Retries (default: 3)\n" +
+		"  -since time        Since\n" +
+		"  -timeout duration  Timeout\n\n"
+
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+	}
+}
+
+func TestCommand_renderHelp_CommandGroups(t *testing.T) {
+	type tcase struct {
+		sort bool
+		want string
+	}
+
+	tests := map[string]tcase{
+		"Declaration order": {
+			sort: false,
+			want: "\nManagement Commands:\n" +
+				"  container  Manage containers\n" +
+				"  volume     Manage volumes\n\n" +
+				"Debug Commands:\n" +
+				"  inspect    Inspect internals\n\n" +
+				"Additional Commands:\n" +
+				"  version    Print the version\n",
+		},
+
+		"Sorted": {
+			sort: true,
+			want: "\nDebug Commands:\n" +
+				"  inspect    Inspect internals\n\n" +
+				"Management Commands:\n" +
+				"  container  Manage containers\n" +
+				"  volume     Manage volumes\n\n" +
+				"Additional Commands:\n" +
+				"  version    Print the version\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := &Command{Name: "app", SortGroups: tc.sort}
+			root.AddGroup("management", "Management Commands")
+			root.AddGroup("debug", "Debug Commands")
+			root.AddGroup("empty", "Empty Commands")
+			root.AddSubcommands(
+				&Command{Name: "volume", Short: "Manage volumes", Group: "management"},
+				&Command{Name: "container", Short: "Manage containers", Group: "management"},
+				&Command{Name: "inspect", Short: "Inspect internals", Group: "debug"},
+				&Command{Name: "version", Short: "Print the version"},
+				&Command{Name: "secret", Short: "Hidden command", Group: "debug", Hidden: true},
+			)
+
+			var b strings.Builder
+
+			if err := root.renderHelp(&b, terminal{width: 80}); err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(b.String(), tc.want) {
+				t.Errorf("Expected output containing := %q, got := %q", tc.want, b.String())
+			}
+		})
+	}
+}
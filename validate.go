@@ -0,0 +1,444 @@
+package scotty
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Struct tag names for validation rules.
+const (
+	tagMin      = "min"
+	tagMax      = "max"
+	tagLen      = "len"
+	tagPattern  = "pattern"
+	tagURL      = "url"
+	tagHostPort = "hostport"
+	tagFile     = "file"
+	tagDir      = "dir"
+	tagPort     = "port"
+)
+
+// fieldRule is the validation rule of the struct field declared by a struct tag.
+type fieldRule struct {
+	// note describes the rule in the help output, e.g. "min: 1".
+	note string
+
+	// check returns the description of the violation, or nil
+	// when the value satisfies the rule.
+	check func(v reflect.Value) error
+}
+
+// fieldValidation holds the validation rules of the struct field checked after parsing.
+type fieldValidation struct {
+	fieldName string
+	flagName  string
+	fieldVal  reflect.Value
+	rules     []fieldRule
+}
+
+// fieldRules returns the validation rules of the field of the type t
+// declared by the struct tags. The rules are checked against the value
+// the field points to when t is a pointer type.
+func fieldRules(tag reflect.StructTag, t reflect.Type) ([]fieldRule, error) {
+	t = indirectType(t)
+
+	var rules []fieldRule
+
+	for _, build := range []struct {
+		tag   string
+		build func(t reflect.Type, arg string) (fieldRule, error)
+	}{
+		{tagMin, minRule},
+		{tagMax, maxRule},
+		{tagLen, lenRule},
+		{tagPattern, patternRule},
+		{tagURL, urlRule},
+		{tagHostPort, hostPortRule},
+		{tagFile, fileRule},
+		{tagDir, dirRule},
+		{tagPort, portRule},
+		{tagOneOf, oneOfRule},
+	} {
+		arg, ok := tag.Lookup(build.tag)
+		if !ok {
+			continue
+		}
+
+		// The oneof tag of string fields makes enum flags.
+		if build.tag == tagOneOf && t.Kind() == reflect.String {
+			continue
+		}
+
+		rule, err := build.build(t, arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s tag %q: %w", build.tag, arg, err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// validateFields checks the validation rules of the bound struct fields and
+// returns FieldValidationError describing the first violated rule of each field.
+// The rules are not checked for the zero values which have not been provided,
+// required tag makes the field mandatory.
+func validateFields(f *FlagSet) []error {
+	var errs []error
+
+	for _, field := range f.validations {
+		v := field.fieldVal
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				continue
+			}
+
+			v = v.Elem()
+		}
+
+		if v.IsZero() && !f.Changed(field.flagName) {
+			continue
+		}
+
+		for _, rule := range field.rules {
+			if err := rule.check(v); err != nil {
+				errs = append(errs, &FieldValidationError{
+					FieldName: field.fieldName,
+					FlagName:  field.flagName,
+					EnvName:   f.envName(field.flagName),
+					Rule:      rule.note,
+					Err:       err,
+				})
+
+				break
+			}
+		}
+	}
+
+	return errs
+}
+
+// minRule requires the number to be at least the argument,
+// or the length of the string, slice or map to be at least the argument.
+func minRule(t reflect.Type, arg string) (fieldRule, error) {
+	compareTo, length, err := comparator(t, arg)
+	if err != nil {
+		return fieldRule{}, err
+	}
+
+	return fieldRule{
+		note: "min: " + arg,
+		check: func(v reflect.Value) error {
+			if compareTo(v) >= 0 {
+				return nil
+			}
+
+			return fmt.Errorf("%s must be at least %s", tern(length, "length", "value"), arg)
+		},
+	}, nil
+}
+
+// maxRule requires the number to be at most the argument,
+// or the length of the string, slice or map to be at most the argument.
+func maxRule(t reflect.Type, arg string) (fieldRule, error) {
+	compareTo, length, err := comparator(t, arg)
+	if err != nil {
+		return fieldRule{}, err
+	}
+
+	return fieldRule{
+		note: "max: " + arg,
+		check: func(v reflect.Value) error {
+			if compareTo(v) <= 0 {
+				return nil
+			}
+
+			return fmt.Errorf("%s must be at most %s", tern(length, "length", "value"), arg)
+		},
+	}, nil
+}
+
+// lenRule requires the length of the string, slice or map to equal the argument.
+func lenRule(t reflect.Type, arg string) (fieldRule, error) {
+	if !hasLength(t) {
+		return fieldRule{}, fmt.Errorf("unsupported type %s", t)
+	}
+
+	compareTo, _, err := comparator(t, arg)
+	if err != nil {
+		return fieldRule{}, err
+	}
+
+	return fieldRule{
+		note: "len: " + arg,
+		check: func(v reflect.Value) error {
+			if compareTo(v) == 0 {
+				return nil
+			}
+
+			return fmt.Errorf("length must be %s", arg)
+		},
+	}, nil
+}
+
+// patternRule requires the whole string, or each string of the slice,
+// to match the regular expression.
+func patternRule(t reflect.Type, arg string) (fieldRule, error) {
+	re, err := regexp.Compile("^(?:" + arg + ")$")
+	if err != nil {
+		return fieldRule{}, err
+	}
+
+	return stringRule(t, "pattern: "+arg, func(s string) error {
+		if re.MatchString(s) {
+			return nil
+		}
+
+		return fmt.Errorf("must match the pattern %s", arg)
+	})
+}
+
+// urlRule requires the string to be an absolute URL with a scheme and a host.
+func urlRule(t reflect.Type, arg string) (fieldRule, error) {
+	if arg != "true" {
+		return fieldRule{}, errors.New(`want "true"`)
+	}
+
+	return stringRule(t, "URL", func(s string) error {
+		if u, err := url.Parse(s); err == nil && u.Scheme != "" && u.Host != "" {
+			return nil
+		}
+
+		return errors.New("must be an absolute URL")
+	})
+}
+
+// hostPortRule requires the string to be a host and a port number separated by a colon.
+func hostPortRule(t reflect.Type, arg string) (fieldRule, error) {
+	if arg != "true" {
+		return fieldRule{}, errors.New(`want "true"`)
+	}
+
+	return stringRule(t, "host:port", func(s string) error {
+		if _, port, err := net.SplitHostPort(s); err == nil && validPort(port, 1) {
+			return nil
+		}
+
+		return errors.New("must be host:port")
+	})
+}
+
+// fileRule requires the string to be a path of an existing file.
+func fileRule(t reflect.Type, arg string) (fieldRule, error) {
+	if arg != "exists" {
+		return fieldRule{}, errors.New(`want "exists"`)
+	}
+
+	return stringRule(t, "existing file", func(s string) error {
+		info, err := os.Stat(s)
+		if err != nil {
+			return errors.New("file must exist")
+		}
+
+		if info.IsDir() {
+			return errors.New("must be a file, not a directory")
+		}
+
+		return nil
+	})
+}
+
+// dirRule requires the string to be a path of an existing directory.
+func dirRule(t reflect.Type, arg string) (fieldRule, error) {
+	if arg != "exists" {
+		return fieldRule{}, errors.New(`want "exists"`)
+	}
+
+	return stringRule(t, "existing directory", func(s string) error {
+		info, err := os.Stat(s)
+		if err != nil {
+			return errors.New("directory must exist")
+		}
+
+		if !info.IsDir() {
+			return errors.New("must be a directory")
+		}
+
+		return nil
+	})
+}
+
+// portRule requires the integer, or the string holding an integer,
+// to be a port number from 1 to 65535.
+func portRule(t reflect.Type, arg string) (fieldRule, error) {
+	if arg != "true" {
+		return fieldRule{}, errors.New(`want "true"`)
+	}
+
+	errPort := errors.New("must be a port number from 1 to 65535")
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fieldRule{note: "port", check: func(v reflect.Value) error {
+			return tern(v.Int() >= 1 && v.Int() <= 65535, nil, errPort)
+		}}, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fieldRule{note: "port", check: func(v reflect.Value) error {
+			return tern(v.Uint() >= 1 && v.Uint() <= 65535, nil, errPort)
+		}}, nil
+
+	default:
+		return stringRule(t, "port", func(s string) error {
+			return tern(validPort(s, 1), nil, errPort)
+		})
+	}
+}
+
+// oneOfRule requires the number, the duration or the byte size to equal
+// one of the comma-separated arguments. The oneof tag of string fields
+// is not a rule, such fields are bound to enum flags.
+func oneOfRule(t reflect.Type, arg string) (fieldRule, error) {
+	if hasLength(t) {
+		return fieldRule{}, fmt.Errorf("unsupported type %s", t)
+	}
+
+	values := strings.Split(arg, ",")
+	compares := make([]func(v reflect.Value) int, 0, len(values))
+
+	for _, value := range values {
+		compareTo, _, err := comparator(t, value)
+		if err != nil {
+			return fieldRule{}, err
+		}
+
+		compares = append(compares, compareTo)
+	}
+
+	allowed := strings.Join(values, ", ")
+
+	return fieldRule{
+		note: "one of: " + allowed,
+		check: func(v reflect.Value) error {
+			for _, compareTo := range compares {
+				if compareTo(v) == 0 {
+					return nil
+				}
+			}
+
+			return fmt.Errorf("must be one of %s", allowed)
+		},
+	}, nil
+}
+
+// stringRule returns the rule checking the string, or each string of the slice.
+func stringRule(t reflect.Type, note string, check func(s string) error) (fieldRule, error) {
+	switch {
+	case t.Kind() == reflect.String:
+		return fieldRule{note: note, check: func(v reflect.Value) error { return check(v.String()) }}, nil
+
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String:
+		return fieldRule{note: note, check: func(v reflect.Value) error {
+			for i := range v.Len() {
+				if err := check(v.Index(i).String()); err != nil {
+					return fmt.Errorf("item %d: %w", i+1, err)
+				}
+			}
+
+			return nil
+		}}, nil
+
+	default:
+		return fieldRule{}, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// comparator returns the function comparing the value of the type t with
+// the argument: the number, the duration, the byte size, or the length
+// of the string, slice or map. It reports whether the length is compared.
+func comparator(t reflect.Type, arg string) (func(v reflect.Value) int, bool, error) {
+	switch {
+	case t == reflect.TypeFor[time.Duration]():
+		bound, err := time.ParseDuration(arg)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return func(v reflect.Value) int { return cmp.Compare(v.Int(), int64(bound)) }, false, nil
+
+	case t == reflect.TypeFor[ByteSize]():
+		bound, err := ParseByteSize(arg)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return func(v reflect.Value) int { return cmp.Compare(v.Uint(), uint64(bound)) }, false, nil
+
+	case hasLength(t):
+		bound, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return func(v reflect.Value) int {
+			n := v.Len()
+			if v.Kind() == reflect.String {
+				n = utf8.RuneCountInString(v.String())
+			}
+
+			return cmp.Compare(n, bound)
+		}, true, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bound, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return func(v reflect.Value) int { return cmp.Compare(v.Int(), bound) }, false, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		bound, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return func(v reflect.Value) int { return cmp.Compare(v.Uint(), bound) }, false, nil
+
+	case reflect.Float32, reflect.Float64:
+		bound, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return func(v reflect.Value) int { return cmp.Compare(v.Float(), bound) }, false, nil
+
+	default:
+		return nil, false, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// hasLength reports whether the length of the values of the type t is validated.
+func hasLength(t reflect.Type) bool {
+	return t.Kind() == reflect.String || t.Kind() == reflect.Slice || t.Kind() == reflect.Map
+}
+
+// validPort reports whether the string is a port number not less than minPort.
+func validPort(s string, minPort uint64) bool {
+	port, err := strconv.ParseUint(s, 10, 16)
+
+	return err == nil && port >= minPort
+}
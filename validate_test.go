@@ -0,0 +1,174 @@
+package scotty
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBindConfig_ValidationTags(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	type config struct {
+		Workers  int           `flag:"workers" default:"4" min:"1" max:"64" usage:"Workers"`
+		Ratio    float64       `flag:"ratio" min:"0.5" usage:"Ratio"`
+		Timeout  time.Duration `flag:"timeout" default:"5s" max:"1m" usage:"Timeout"`
+		Upload   ByteSize      `flag:"upload" max:"1MiB" usage:"Upload"`
+		Code     string        `flag:"code" len:"3" usage:"Code"`
+		Name     string        `flag:"name" pattern:"[a-z]+" usage:"Name"`
+		Tags     []string      `flag:"tag" max:"2" pattern:"[a-z]+" usage:"Tags"`
+		Endpoint string        `flag:"endpoint" url:"true" usage:"Endpoint"`
+		Listen   string        `flag:"listen" hostport:"true" usage:"Listen"`
+		Config   string        `flag:"config" file:"exists" usage:"Config"`
+		Data     string        `flag:"data" dir:"exists" usage:"Data"`
+		Port     int           `flag:"port" port:"true" usage:"Port"`
+		Limit    *int          `flag:"limit" max:"10" usage:"Limit"`
+		Replicas int           `flag:"replicas" oneof:"1,3,5" usage:"Replicas"`
+		Interval time.Duration `flag:"interval" oneof:"1s,1m" usage:"Interval"`
+	}
+
+	tests := map[string]struct {
+		args  []string
+		field string
+		rule  string
+	}{
+		"Valid": {
+			args: []string{
+				"-workers=8", "-ratio=0.5", "-timeout=1m", "-upload=1MiB", "-code=abc", "-name=scotty",
+				"-tag=a,b", "-endpoint=https://example.com/api", "-listen=:8080", "-config=" + file,
+				"-data=" + dir, "-port=443", "-limit=10", "-replicas=3", "-interval=1m",
+			},
+		},
+		"ZeroValuesNotProvided": {args: []string{}},
+		"Min":                   {args: []string{"-workers=0"}, field: "Workers", rule: "min: 1"},
+		"Max":                   {args: []string{"-workers=65"}, field: "Workers", rule: "max: 64"},
+		"MinFloat":              {args: []string{"-ratio=0.4"}, field: "Ratio", rule: "min: 0.5"},
+		"MaxDuration":           {args: []string{"-timeout=2m"}, field: "Timeout", rule: "max: 1m"},
+		"MaxByteSize":           {args: []string{"-upload=2MiB"}, field: "Upload", rule: "max: 1MiB"},
+		"Len":                   {args: []string{"-code=ab"}, field: "Code", rule: "len: 3"},
+		"Pattern":               {args: []string{"-name=Scotty"}, field: "Name", rule: "pattern: [a-z]+"},
+		"MaxItems":              {args: []string{"-tag=a,b,c"}, field: "Tags", rule: "max: 2"},
+		"PatternItems":          {args: []string{"-tag=a,B"}, field: "Tags", rule: "pattern: [a-z]+"},
+		"URL":                   {args: []string{"-endpoint=example.com"}, field: "Endpoint", rule: "URL"},
+		"HostPort":              {args: []string{"-listen=localhost"}, field: "Listen", rule: "host:port"},
+		"HostPortZero":          {args: []string{"-listen=localhost:0"}, field: "Listen", rule: "host:port"},
+		"FileMissing":           {args: []string{"-config=" + filepath.Join(dir, "missing")}, field: "Config", rule: "existing file"},
+		"FileIsDir":             {args: []string{"-config=" + dir}, field: "Config", rule: "existing file"},
+		"Dir":                   {args: []string{"-data=" + file}, field: "Data", rule: "existing directory"},
+		"PortZero":              {args: []string{"-port=0"}, field: "Port", rule: "port"},
+		"PortRange":             {args: []string{"-port=65536"}, field: "Port", rule: "port"},
+		"Pointer":               {args: []string{"-limit=11"}, field: "Limit", rule: "max: 10"},
+		"OneOf":                 {args: []string{"-replicas=2"}, field: "Replicas", rule: "one of: 1, 3, 5"},
+		"OneOfDuration":         {args: []string{"-interval=2s"}, field: "Interval", rule: "one of: 1s, 1m"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			helperDisableStdout(t)
+
+			cmd := &Command{
+				Name: "test",
+				Run:  func(cmd *Command, args []string) error { return nil },
+			}
+
+			if err := cmd.BindConfig(&config{}); err != nil {
+				t.Fatalf("BindConfig failed: %v", err)
+			}
+
+			err := cmd.execCommand(tc.args)
+
+			if tc.field == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			var valErr *FieldValidationError
+			if !errors.As(err, &valErr) {
+				t.Fatalf("expected FieldValidationError, got %v", err)
+			}
+
+			if valErr.FieldName != tc.field || valErr.Rule != tc.rule {
+				t.Errorf("field=%s, rule=%s, want field=%s, rule=%s", valErr.FieldName, valErr.Rule, tc.field, tc.rule)
+			}
+		})
+	}
+}
+
+func TestBindConfig_InvalidValidationTags(t *testing.T) {
+	tests := map[string]any{
+		"MinOfBool": &struct {
+			V bool `flag:"v" min:"1"`
+		}{},
+		"InvalidMin": &struct {
+			V int `flag:"v" min:"one"`
+		}{},
+		"LenOfInt": &struct {
+			V int `flag:"v" len:"1"`
+		}{},
+		"InvalidPattern": &struct {
+			V string `flag:"v" pattern:"("`
+		}{},
+		"URLOfInt": &struct {
+			V int `flag:"v" url:"true"`
+		}{},
+		"InvalidFileTag": &struct {
+			V string `flag:"v" file:"true"`
+		}{},
+		"InvalidPortTag": &struct {
+			V int `flag:"v" port:"yes"`
+		}{},
+		"InvalidDuration": &struct {
+			V time.Duration `flag:"v" max:"10"`
+		}{},
+		"InvalidOneOf": &struct {
+			V int `flag:"v" oneof:"1,two"`
+		}{},
+		"OneOfSlice": &struct {
+			V []int `flag:"v" oneof:"1,2"`
+		}{},
+	}
+
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := (&Command{Name: "test"}).BindConfig(cfg); err == nil {
+				t.Error("expected error for invalid validation tag, got nil")
+			}
+		})
+	}
+}
+
+func TestBindConfig_ValidationHelp(t *testing.T) {
+	type config struct {
+		Port int    `flag:"port" min:"1024" max:"65535" usage:"Port"`
+		Data string `flag:"data" dir:"exists" usage:"Data directory"`
+	}
+
+	cmd := &Command{Name: "test"}
+
+	if err := cmd.BindConfig(&config{}); err != nil {
+		t.Fatalf("BindConfig failed: %v", err)
+	}
+
+	var b strings.Builder
+
+	if err := cmd.renderHelp(&b, terminal{width: 120}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"Port (min: 1024; max: 65535)", "Data directory (existing directory)"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Expected output containing := %q, got := %q", want, b.String())
+		}
+	}
+}